@@ -5,11 +5,12 @@ import (
 	"github.com/bryanl/woowoo/pipeline"
 	"github.com/bryanl/woowoo/pkg/client"
 	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // Apply applies an environment.
-func Apply(fs afero.Fs, env string, options client.ApplyOptions) error {
-	s, err := newApply(fs, env, options)
+func Apply(fs afero.Fs, env string, options client.ApplyOptions, opts ...ApplyOpt) error {
+	s, err := newApply(fs, env, options, opts...)
 	if err != nil {
 		return err
 	}
@@ -17,17 +18,29 @@ func Apply(fs afero.Fs, env string, options client.ApplyOptions) error {
 	return s.Run()
 }
 
+// ApplyOpt is an option for configuring Apply.
+type ApplyOpt func(*apply)
+
+// ApplyWithSkipPolicies skips the policy checks which run before objects
+// are applied.
+func ApplyWithSkipPolicies(skip bool) ApplyOpt {
+	return func(s *apply) {
+		s.skipPolicies = skip
+	}
+}
+
 // Apply is a apply Action
 type apply struct {
-	env        string
-	components []string
-	options    client.ApplyOptions
+	env          string
+	components   []string
+	options      client.ApplyOptions
+	skipPolicies bool
 
 	*base
 }
 
 // NewApply creates an instance of Apply.
-func newApply(fs afero.Fs, env string, options client.ApplyOptions) (*apply, error) {
+func newApply(fs afero.Fs, env string, options client.ApplyOptions, opts ...ApplyOpt) (*apply, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
@@ -39,6 +52,10 @@ func newApply(fs afero.Fs, env string, options client.ApplyOptions) (*apply, err
 		base:    b,
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	return s, nil
 }
 
@@ -46,11 +63,22 @@ func newApply(fs afero.Fs, env string, options client.ApplyOptions) (*apply, err
 func (s *apply) Run() error {
 	p := pipeline.New(s.app, s.env)
 
-	objects, err := p.Objects(s.components)
+	rendered, err := p.ComponentObjects(s.components)
 	if err != nil {
 		return err
 	}
 
+	if !s.skipPolicies {
+		if err = checkPolicies(s.app, s.env, rendered); err != nil {
+			return err
+		}
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, rc := range rendered {
+		objects = append(objects, rc.Objects...)
+	}
+
 	// TODO: create better semantics around apply
 	c := k8sutil.ApplyCmd{
 		Env:          s.env,
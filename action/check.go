@@ -0,0 +1,82 @@
+package action
+
+import (
+	"os"
+
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/bryanl/woowoo/policy"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Check checks the objects in an environment against the app's policies.
+func Check(fs afero.Fs, env string) error {
+	c, err := newCheck(fs, env)
+	if err != nil {
+		return err
+	}
+
+	return c.Run()
+}
+
+type check struct {
+	env string
+
+	*base
+}
+
+func newCheck(fs afero.Fs, env string) (*check, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &check{
+		env:  env,
+		base: b,
+	}
+
+	return c, nil
+}
+
+// Run runs the action.
+func (c *check) Run() error {
+	p := pipeline.New(c.app, c.env)
+
+	rendered, err := p.ComponentObjects(nil)
+	if err != nil {
+		return err
+	}
+
+	return checkPolicies(c.app, c.env, rendered)
+}
+
+// checkPolicies reports policy violations for rendered components. It returns
+// an error if any violations have error severity.
+func checkPolicies(a app.App, env string, rendered []pipeline.RenderedComponent) error {
+	checker := policy.NewChecker(a, env)
+
+	violations, err := checker.Check(rendered)
+	if err != nil {
+		return errors.Wrap(err, "check policies")
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	table := ksutil.NewTable(os.Stdout)
+	table.SetHeader([]string{"policy", "severity", "component", "object", "message"})
+	for _, v := range violations {
+		table.Append([]string{v.Policy, v.Severity, v.Component, v.Object, v.Message})
+	}
+	table.Render()
+
+	if policy.HasErrors(violations) {
+		return errors.Errorf("environment %q has policy errors", env)
+	}
+
+	return nil
+}
@@ -11,8 +11,8 @@ import (
 )
 
 // ComponentList create a list of components in a namespace.
-func ComponentList(fs afero.Fs, namespace, output string) error {
-	cl, err := newComponentList(fs, namespace, output)
+func ComponentList(fs afero.Fs, namespace, output string, opts ...ComponentListOpt) error {
+	cl, err := newComponentList(fs, namespace, output, opts...)
 	if err != nil {
 		return err
 	}
@@ -20,14 +20,27 @@ func ComponentList(fs afero.Fs, namespace, output string) error {
 	return cl.run()
 }
 
+// ComponentListOpt is an option for configuring ComponentList.
+type ComponentListOpt func(*componentList)
+
+// ComponentListWithEnv lists the components as seen by an environment.
+// Components overridden by the environment are marked.
+func ComponentListWithEnv(envName string) ComponentListOpt {
+	return func(cl *componentList) {
+		cl.envName = envName
+	}
+}
+
 type componentList struct {
-	nsName string
-	output string
+	nsName     string
+	output     string
+	envName    string
+	overridden map[string]bool
 
 	*base
 }
 
-func newComponentList(fs afero.Fs, namespace, output string) (*componentList, error) {
+func newComponentList(fs afero.Fs, namespace, output string, opts ...ComponentListOpt) (*componentList, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
@@ -39,6 +52,10 @@ func newComponentList(fs afero.Fs, namespace, output string) (*componentList, er
 		base:   b,
 	}
 
+	for _, opt := range opts {
+		opt(cl)
+	}
+
 	return cl, nil
 }
 
@@ -48,6 +65,19 @@ func (cl *componentList) run() error {
 		return err
 	}
 
+	if cl.envName != "" {
+		if _, err = cl.app.Environment(cl.envName); err != nil {
+			return err
+		}
+
+		ns = ns.InEnvironment(cl.envName)
+
+		cl.overridden, err = ns.Overridden()
+		if err != nil {
+			return err
+		}
+	}
+
 	components, err := ns.Components()
 	if err != nil {
 		return err
@@ -76,13 +106,37 @@ func (cl *componentList) listComponents(components []component.Component) {
 	sort.Strings(list)
 
 	table := ksutil.NewTable(os.Stdout)
-	table.SetHeader([]string{"component"})
+	table.SetHeader(cl.header("component"))
 	for _, item := range list {
-		table.Append([]string{item})
+		table.Append(cl.row(item, item))
 	}
 	table.Render()
 }
 
+// header adds the overridden column to a header when listing for an
+// environment.
+func (cl *componentList) header(columns ...string) []string {
+	if cl.envName == "" {
+		return columns
+	}
+
+	return append(columns, "overridden")
+}
+
+// row adds the overridden column to a row when listing for an environment.
+func (cl *componentList) row(componentName string, columns ...string) []string {
+	if cl.envName == "" {
+		return columns
+	}
+
+	mark := ""
+	if cl.overridden[componentName] {
+		mark = "*"
+	}
+
+	return append(columns, mark)
+}
+
 func (cl *componentList) listComponentsWide(components []component.Component) error {
 	var rows [][]string
 	for _, c := range components {
@@ -92,14 +146,14 @@ func (cl *componentList) listComponentsWide(components []component.Component) er
 		}
 
 		for _, summary := range summaries {
-			row := []string{
+			row := cl.row(summary.ComponentName,
 				summary.ComponentName,
 				summary.Type,
 				summary.IndexStr,
 				summary.APIVersion,
 				summary.Kind,
 				summary.Name,
-			}
+			)
 
 			rows = append(rows, row)
 
@@ -107,7 +161,7 @@ func (cl *componentList) listComponentsWide(components []component.Component) er
 	}
 
 	table := ksutil.NewTable(os.Stdout)
-	table.SetHeader([]string{"component", "type", "index", "apiversion", "kind", "name"})
+	table.SetHeader(cl.header("component", "type", "index", "apiversion", "kind", "name"))
 	table.AppendBulk(rows)
 	table.Render()
 
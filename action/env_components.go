@@ -0,0 +1,73 @@
+package action
+
+import (
+	"github.com/bryanl/woowoo/component"
+	"github.com/spf13/afero"
+)
+
+// EnvComponentsEnable includes a component in an environment.
+func EnvComponentsEnable(fs afero.Fs, envName, componentName string) error {
+	ec, err := newEnvComponents(fs, envName, componentName, true)
+	if err != nil {
+		return err
+	}
+
+	return ec.Run()
+}
+
+// EnvComponentsDisable excludes a component from an environment.
+func EnvComponentsDisable(fs afero.Fs, envName, componentName string) error {
+	ec, err := newEnvComponents(fs, envName, componentName, false)
+	if err != nil {
+		return err
+	}
+
+	return ec.Run()
+}
+
+type envComponents struct {
+	envName       string
+	componentName string
+	enable        bool
+
+	*base
+}
+
+func newEnvComponents(fs afero.Fs, envName, componentName string, enable bool) (*envComponents, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	ec := &envComponents{
+		envName:       envName,
+		componentName: componentName,
+		enable:        enable,
+		base:          b,
+	}
+
+	return ec, nil
+}
+
+func (ec *envComponents) Run() error {
+	if _, err := ec.app.Environment(ec.envName); err != nil {
+		return err
+	}
+
+	if _, err := component.ExtractComponent(ec.app, ec.componentName); err != nil {
+		return err
+	}
+
+	selection, err := component.ReadSelection(ec.app, ec.envName)
+	if err != nil {
+		return err
+	}
+
+	if ec.enable {
+		selection.Enable(ec.componentName)
+	} else {
+		selection.Disable(ec.componentName)
+	}
+
+	return component.WriteSelection(ec.app, ec.envName, selection)
+}
@@ -0,0 +1,64 @@
+package action
+
+import (
+	"os"
+
+	"github.com/bryanl/woowoo/images"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/spf13/afero"
+)
+
+// ImagesList lists the container images in an environment.
+func ImagesList(fs afero.Fs, env string) error {
+	il, err := newImagesList(fs, env)
+	if err != nil {
+		return err
+	}
+
+	return il.Run()
+}
+
+type imagesList struct {
+	env string
+
+	*base
+}
+
+func newImagesList(fs afero.Fs, env string) (*imagesList, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	il := &imagesList{
+		env:  env,
+		base: b,
+	}
+
+	return il, nil
+}
+
+// Run runs the action.
+func (il *imagesList) Run() error {
+	p := pipeline.New(il.app, il.env)
+
+	rendered, err := p.ComponentObjects(nil)
+	if err != nil {
+		return err
+	}
+
+	list, err := images.List(rendered)
+	if err != nil {
+		return err
+	}
+
+	table := ksutil.NewTable(os.Stdout)
+	table.SetHeader([]string{"component", "object", "container", "image", "param"})
+	for _, image := range list {
+		table.Append([]string{image.Component, image.Object, image.Container, image.Image, image.Param})
+	}
+	table.Render()
+
+	return nil
+}
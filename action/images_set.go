@@ -0,0 +1,113 @@
+package action
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/images"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ImagesSet sets the tag for an image repository in all params which
+// reference it.
+func ImagesSet(fs afero.Fs, repo, tag string, opts ...ImagesSetOpt) error {
+	is, err := newImagesSet(fs, repo, tag, opts...)
+	if err != nil {
+		return err
+	}
+
+	return is.Run()
+}
+
+// ImagesSetOpt is an option for configuring ImagesSet.
+type ImagesSetOpt func(*imagesSet)
+
+// ImagesSetWithDryRun reports the changes without writing them.
+func ImagesSetWithDryRun(dryRun bool) ImagesSetOpt {
+	return func(is *imagesSet) {
+		is.dryRun = dryRun
+	}
+}
+
+type imagesSet struct {
+	repo   string
+	tag    string
+	dryRun bool
+
+	*base
+}
+
+func newImagesSet(fs afero.Fs, repo, tag string, opts ...ImagesSetOpt) (*imagesSet, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	is := &imagesSet{
+		repo: repo,
+		tag:  tag,
+		base: b,
+	}
+
+	for _, opt := range opts {
+		opt(is)
+	}
+
+	return is, nil
+}
+
+// Run runs the action.
+func (is *imagesSet) Run() error {
+	if strings.ContainsAny(is.tag, ":@/") {
+		return errors.Errorf("invalid tag %q", is.tag)
+	}
+
+	namespaces, err := component.Namespaces(is.app)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, ns := range namespaces {
+		b, err := afero.ReadFile(is.app.Fs(), ns.ParamsPath())
+		if err != nil {
+			return err
+		}
+
+		updated, changes, err := images.RetagParams(string(b), is.repo, is.tag)
+		if err != nil {
+			return errors.Wrapf(err, "update params for %s", ns.Name())
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		relPath, err := filepath.Rel(is.app.Root(), ns.ParamsPath())
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			fmt.Printf("%s: %s %s -> %s\n", relPath, strings.Join(change.Path, "."), change.From, change.To)
+		}
+		count += len(changes)
+
+		if is.dryRun {
+			continue
+		}
+
+		if err := afero.WriteFile(is.app.Fs(), ns.ParamsPath(), []byte(updated), 0644); err != nil {
+			return err
+		}
+	}
+
+	if count == 0 {
+		fmt.Printf("no params reference %s\n", is.repo)
+	}
+
+	return nil
+}
@@ -2,9 +2,11 @@ package action
 
 import (
 	"bytes"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/bryanl/woowoo/component"
 	kscomponent "github.com/ksonnet/ksonnet/component"
 	ksparam "github.com/ksonnet/ksonnet/metadata/params"
 	"github.com/ksonnet/ksonnet/prototype"
@@ -55,6 +57,15 @@ func (i *componentImport) Run() error {
 
 	var paths []string
 	if pathFi.IsDir() {
+		isChart, err := component.IsChartDir(i.app.Fs(), i.path)
+		if err != nil {
+			return err
+		}
+
+		if isChart {
+			return i.importChart()
+		}
+
 		fis, err := afero.ReadDir(i.app.Fs(), i.path)
 		if err != nil {
 			return err
@@ -77,6 +88,64 @@ func (i *componentImport) Run() error {
 	return nil
 }
 
+// importChart copies an unpacked Helm chart into a component namespace and
+// seeds the component's params from the chart's values.yaml.
+func (i *componentImport) importChart() error {
+	ns, err := component.GetNamespace(i.app, i.nsName)
+	if err != nil {
+		return err
+	}
+
+	dest := filepath.Join(ns.Dir(), filepath.Base(filepath.Clean(i.path)))
+
+	exists, err := afero.Exists(i.app.Fs(), dest)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return errors.Errorf("%s already exists", dest)
+	}
+
+	if err := copyDir(i.app.Fs(), i.path, dest); err != nil {
+		return errors.Wrap(err, "copy chart")
+	}
+
+	chart := component.NewChart(i.app, ns.Name(), dest, ns.ParamsPath())
+	if err := chart.SeedParams(); err != nil {
+		return errors.Wrap(err, "seed params from chart values")
+	}
+
+	return nil
+}
+
+// copyDir copies the contents of directory src to dest.
+func copyDir(fs afero.Fs, src, dest string) error {
+	return afero.Walk(fs, src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, rel)
+
+		if fi.IsDir() {
+			return fs.MkdirAll(target, 0755)
+		}
+
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+
+		return afero.WriteFile(fs, target, b, 0644)
+	})
+}
+
 func (i *componentImport) importFile(fileName string) error {
 	var name bytes.Buffer
 	if i.nsName != "" {
@@ -1,9 +1,8 @@
 package action
 
 import (
-	"strings"
-
 	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/params"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
@@ -21,6 +20,13 @@ func ParamDelete(fs afero.Fs, componentName, path string, opts ...ParamDeleteOpt
 // ParamDeleteOpt is an option for configuration ParamDelete.
 type ParamDeleteOpt func(*paramDelete)
 
+// ParamDeleteWithEnv deletes the param from an environment's params.
+func ParamDeleteWithEnv(envName string) ParamDeleteOpt {
+	return func(pd *paramDelete) {
+		pd.envName = envName
+	}
+}
+
 // ParamDeleteWithIndex sets the index for the delete option.
 func ParamDeleteWithIndex(index int) ParamDeleteOpt {
 	return func(pd *paramDelete) {
@@ -33,6 +39,7 @@ type paramDelete struct {
 	componentName string
 	rawPath       string
 	index         int
+	envName       string
 
 	*base
 }
@@ -59,13 +66,20 @@ func newParamDelete(fs afero.Fs, componentName, path string, opts ...ParamDelete
 
 // Run runs the action.
 func (pd *paramDelete) Run() error {
-	path := strings.Split(pd.rawPath, ".")
+	path := params.SplitPath(pd.rawPath)
 
 	c, err := component.ExtractComponent(pd.app, pd.componentName)
 	if err != nil {
 		return errors.Wrap(err, "could not find component")
 	}
 
+	if pd.envName != "" {
+		key := component.ParamsEntry(c, pd.index)
+		return updateEnvParams(pd.app, pd.envName, func(src string) (string, error) {
+			return params.EnvDelete(path, src, key, "components")
+		})
+	}
+
 	options := component.ParamOptions{
 		Index: pd.index,
 	}
@@ -0,0 +1,234 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ParamDiff shows the differences between the effective params of two
+// environments.
+func ParamDiff(fs afero.Fs, env1, env2, nsName string, opts ...ParamDiffOpt) error {
+	pd, err := newParamDiff(fs, env1, env2, nsName, opts...)
+	if err != nil {
+		return err
+	}
+
+	return pd.Run()
+}
+
+// ParamDiffOpt is an option for configuring ParamDiff.
+type ParamDiffOpt func(*paramDiff)
+
+// ParamDiffWithAll includes params which are equal in both environments.
+func ParamDiffWithAll(all bool) ParamDiffOpt {
+	return func(pd *paramDiff) {
+		pd.all = all
+	}
+}
+
+// ParamDiffWithReveal decrypts secrets instead of redacting them.
+func ParamDiffWithReveal(reveal bool) ParamDiffOpt {
+	return func(pd *paramDiff) {
+		pd.reveal = reveal
+	}
+}
+
+type paramDiff struct {
+	env1   string
+	env2   string
+	nsName string
+	all    bool
+	reveal bool
+	out    io.Writer
+	// key decrypts secrets to compare them, and to show them if they are
+	// revealed.
+	key *params.SecretKey
+
+	*base
+}
+
+func newParamDiff(fs afero.Fs, env1, env2, nsName string, opts ...ParamDiffOpt) (*paramDiff, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	pd := &paramDiff{
+		env1:   env1,
+		env2:   env2,
+		nsName: nsName,
+		out:    os.Stdout,
+		base:   b,
+	}
+
+	for _, opt := range opts {
+		opt(pd)
+	}
+
+	return pd, nil
+}
+
+func (pd *paramDiff) Run() error {
+	ns, err := component.GetNamespace(pd.app, pd.nsName)
+	if err != nil {
+		return errors.Wrap(err, "could not find namespace")
+	}
+
+	left, err := pd.envParams(pd.env1, ns)
+	if err != nil {
+		return err
+	}
+
+	right, err := pd.envParams(pd.env2, ns)
+	if err != nil {
+		return err
+	}
+
+	if pd.reveal {
+		if _, err := pd.secretKey(); err != nil {
+			return err
+		}
+	}
+
+	table := ksutil.NewTable(pd.out)
+	table.SetHeader([]string{"COMPONENT", "KEY", pd.env1, pd.env2, "STATUS"})
+
+	for _, d := range params.Diff(left, right) {
+		if d.Status == params.DiffChanged {
+			equal, err := pd.secretsEqual(d.Left, d.Right)
+			if err != nil {
+				return errors.Wrapf(err, "compare %s.%s", d.Component, d.Key)
+			}
+
+			if equal {
+				d.Status = params.DiffEqual
+			}
+		}
+
+		if d.Status == params.DiffEqual && !pd.all {
+			continue
+		}
+
+		lStr, err := pd.value(d.Left, d.Status != params.DiffRightOnly)
+		if err != nil {
+			return err
+		}
+
+		rStr, err := pd.value(d.Right, d.Status != params.DiffLeftOnly)
+		if err != nil {
+			return err
+		}
+
+		table.Append([]string{d.Component, d.Key, lStr, rStr, pd.status(d.Status)})
+	}
+
+	table.Render()
+
+	return nil
+}
+
+// envParams resolves the effective params of a namespace in an environment.
+// Secrets stay encrypted.
+func (pd *paramDiff) envParams(envName string, ns component.Namespace) (map[string]map[string]interface{}, error) {
+	if _, err := pd.app.Environment(envName); err != nil {
+		return nil, err
+	}
+
+	p := pipeline.New(pd.app, envName, pipeline.Sealed())
+	s, err := p.EnvParameters(ns.Name())
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve parameters for environment %s", envName)
+	}
+
+	return decodeComponentParams(s)
+}
+
+func (pd *paramDiff) status(status params.DiffStatus) string {
+	switch status {
+	case params.DiffLeftOnly:
+		return fmt.Sprintf("only in %s", pd.env1)
+	case params.DiffRightOnly:
+		return fmt.Sprintf("only in %s", pd.env2)
+	default:
+		return string(status)
+	}
+}
+
+// secretsEqual reports if changed values are equal once their secrets are
+// decrypted. Secrets are encrypted with a random nonce, so equal secrets have
+// different envelopes.
+func (pd *paramDiff) secretsEqual(left, right interface{}) (bool, error) {
+	l, err := json.Marshal(left)
+	if err != nil {
+		return false, err
+	}
+
+	r, err := json.Marshal(right)
+	if err != nil {
+		return false, err
+	}
+
+	if !params.HasSecrets(string(l)) && !params.HasSecrets(string(r)) {
+		return false, nil
+	}
+
+	key, err := pd.secretKey()
+	if err != nil {
+		return false, err
+	}
+
+	lPlain, err := key.DecryptJSON(string(l))
+	if err != nil {
+		return false, err
+	}
+
+	rPlain, err := key.DecryptJSON(string(r))
+	if err != nil {
+		return false, err
+	}
+
+	return lPlain == rPlain, nil
+}
+
+// secretKey loads the app's secret key the first time it is needed.
+func (pd *paramDiff) secretKey() (*params.SecretKey, error) {
+	if pd.key != nil {
+		return pd.key, nil
+	}
+
+	key, err := params.LoadSecretKey(pd.app.Fs(), pd.app.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	pd.key = key
+	return key, nil
+}
+
+// value formats a value. Secrets are redacted unless they are revealed.
+func (pd *paramDiff) value(v interface{}, exists bool) (string, error) {
+	if !exists {
+		return "", nil
+	}
+
+	s, err := component.ParamValue(v)
+	if err != nil {
+		return "", err
+	}
+
+	var key *params.SecretKey
+	if pd.reveal {
+		key = pd.key
+	}
+
+	return params.RevealSecrets(s, key)
+}
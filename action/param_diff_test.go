@@ -0,0 +1,93 @@
+package action
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamDiff_secrets(t *testing.T) {
+	secretKey := []byte("0123456789abcdef0123456789abcdef")
+	key, err := params.NewSecretKey(secretKey)
+	require.NoError(t, err)
+
+	encrypt := func(v interface{}) string {
+		envelope, err := key.Encrypt(v)
+		require.NoError(t, err)
+		return envelope
+	}
+
+	cases := []struct {
+		name     string
+		reveal   bool
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "redacted",
+			contains: []string{"password", "<secret>"},
+			excludes: []string{"hunter2", "swordfish", "ENC[", "username"},
+		},
+		{
+			name:     "revealed",
+			reveal:   true,
+			contains: []string{"password", `"hunter2"`, `"swordfish"`},
+			excludes: []string{"ENC[", "username"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, fs := appMock("/app")
+			a.On("Environment", "default").Return(&app.EnvironmentSpec{}, nil)
+			a.On("Environment", "prod").Return(&app.EnvironmentSpec{}, nil)
+
+			writeFile(t, fs, "/app/.secret.key", base64.StdEncoding.EncodeToString(secretKey))
+			writeFile(t, fs, "/app/components/db.jsonnet", `{}`)
+			writeFile(t, fs, "/app/components/params.libsonnet", `{
+  components: {
+    db: {
+      password: "`+encrypt("hunter2")+`",
+      username: "`+encrypt("admin")+`",
+    },
+  },
+}
+`)
+			writeFile(t, fs, "/app/environments/default/params.libsonnet", `std.extVar("__ksonnet/params")`)
+			writeFile(t, fs, "/app/environments/prod/params.libsonnet", `local params = std.extVar("__ksonnet/params");
+
+params + {
+  components+: {
+    db+: {
+      password: "`+encrypt("swordfish")+`",
+      username: "`+encrypt("admin")+`",
+    },
+  },
+}
+`)
+
+			var buf bytes.Buffer
+			pd := &paramDiff{
+				env1:   "default",
+				env2:   "prod",
+				reveal: tc.reveal,
+				out:    &buf,
+				base:   &base{app: a},
+			}
+
+			require.NoError(t, pd.Run())
+
+			for _, s := range tc.contains {
+				require.Contains(t, buf.String(), s)
+			}
+
+			for _, s := range tc.excludes {
+				require.NotContains(t, buf.String(), s)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package action
+
+import (
+	"github.com/bryanl/woowoo/component"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+)
+
+// updateEnvParams rewrites an environment's params with fn.
+func updateEnvParams(a app.App, envName string, fn func(string) (string, error)) error {
+	if _, err := a.Environment(envName); err != nil {
+		return err
+	}
+
+	src, err := component.ReadEnvParams(a, envName)
+	if err != nil {
+		return errors.Wrapf(err, "read params for environment %s", envName)
+	}
+
+	updated, err := fn(src)
+	if err != nil {
+		return errors.Wrapf(err, "update params for environment %s", envName)
+	}
+
+	return component.WriteEnvParams(a, envName, updated)
+}
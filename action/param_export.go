@@ -0,0 +1,177 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/params"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ParamExport exports the params for a namespace as a YAML or JSON document.
+func ParamExport(fs afero.Fs, nsName string, opts ...ParamExportOpt) error {
+	pe, err := newParamExport(fs, nsName, opts...)
+	if err != nil {
+		return err
+	}
+
+	return pe.Run()
+}
+
+// ParamExportOpt is an option for configuring ParamExport.
+type ParamExportOpt func(*paramExport)
+
+// ParamExportWithEnv exports the effective params for an environment.
+func ParamExportWithEnv(envName string) ParamExportOpt {
+	return func(pe *paramExport) {
+		pe.envName = envName
+	}
+}
+
+// ParamExportWithOutput sets the output format. Valid formats are yaml and
+// json.
+func ParamExportWithOutput(output string) ParamExportOpt {
+	return func(pe *paramExport) {
+		pe.output = output
+	}
+}
+
+type paramExport struct {
+	nsName  string
+	envName string
+	output  string
+	out     io.Writer
+
+	*base
+}
+
+func newParamExport(fs afero.Fs, nsName string, opts ...ParamExportOpt) (*paramExport, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	pe := &paramExport{
+		nsName: nsName,
+		output: "yaml",
+		out:    os.Stdout,
+		base:   b,
+	}
+
+	for _, opt := range opts {
+		opt(pe)
+	}
+
+	return pe, nil
+}
+
+func (pe *paramExport) Run() error {
+	if pe.output != "yaml" && pe.output != "json" {
+		return errors.Errorf("unknown output format %q", pe.output)
+	}
+
+	ns, err := component.GetNamespace(pe.app, pe.nsName)
+	if err != nil {
+		return errors.Wrap(err, "could not find namespace")
+	}
+
+	var doc *params.Document
+	if pe.envName == "" {
+		doc, err = namespaceDocument(pe.app, ns, pe.nsName)
+	} else {
+		doc, err = envDocument(pe.app, ns, pe.nsName, pe.envName)
+	}
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	if pe.output == "json" {
+		b, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		b, err = yaml.Marshal(doc)
+	}
+	if err != nil {
+		return errors.Wrap(err, "encode params")
+	}
+
+	_, err = fmt.Fprintln(pe.out, string(b))
+	return err
+}
+
+// namespaceDocument creates a params document from a namespace's params.
+func namespaceDocument(a app.App, ns component.Namespace, nsName string) (*params.Document, error) {
+	src, err := afero.ReadFile(a.Fs(), ns.ParamsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &params.Document{
+		Namespace:  nsName,
+		Components: make(map[string]map[string]interface{}),
+	}
+
+	global, err := params.ToMap("", string(src), "global")
+	if err == nil {
+		doc.Global = global
+	}
+
+	components, err := params.ToMap("", string(src), "components")
+	if err != nil {
+		return nil, errors.Wrap(err, "read component params")
+	}
+
+	for entry, v := range components {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("params for %q are not an object", entry)
+		}
+
+		doc.Components[entry] = m
+	}
+
+	return doc, nil
+}
+
+// envDocument creates a params document from the effective params of a
+// namespace in an environment. Secrets stay encrypted.
+func envDocument(a app.App, ns component.Namespace, nsName, envName string) (*params.Document, error) {
+	if _, err := a.Environment(envName); err != nil {
+		return nil, err
+	}
+
+	p := pipeline.New(a, envName, pipeline.Sealed())
+	s, err := p.EnvParameters(ns.Name())
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve parameters for environment %s", envName)
+	}
+
+	components, err := decodeComponentParams(s)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &params.Document{
+		Namespace:   nsName,
+		Environment: envName,
+		Components:  components,
+	}
+
+	envSrc, err := component.ReadEnvParams(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	if global, err := params.EnvToMap("", envSrc, "global"); err == nil {
+		doc.Global = global
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,211 @@
+package action
+
+import (
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ParamImport imports params from a YAML or JSON document created by
+// ParamExport.
+func ParamImport(fs afero.Fs, fileName string, opts ...ParamImportOpt) error {
+	pi, err := newParamImport(fs, fileName, opts...)
+	if err != nil {
+		return err
+	}
+
+	return pi.Run()
+}
+
+// ParamImportOpt is an option for configuring ParamImport.
+type ParamImportOpt func(*paramImport)
+
+// ParamImportWithDryRun reports the changes without writing them.
+func ParamImportWithDryRun(dryRun bool) ParamImportOpt {
+	return func(pi *paramImport) {
+		pi.dryRun = dryRun
+	}
+}
+
+type paramImport struct {
+	fileName string
+	dryRun   bool
+
+	*base
+}
+
+func newParamImport(fs afero.Fs, fileName string, opts ...ParamImportOpt) (*paramImport, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	pi := &paramImport{
+		fileName: fileName,
+		base:     b,
+	}
+
+	for _, opt := range opts {
+		opt(pi)
+	}
+
+	return pi, nil
+}
+
+// globalEntry labels global params in the import report.
+const globalEntry = "<global>"
+
+// importTarget is the component entry an imported param is written to.
+type importTarget struct {
+	component component.Component
+	index     int
+}
+
+func (pi *paramImport) Run() error {
+	b, err := afero.ReadFile(pi.app.Fs(), pi.fileName)
+	if err != nil {
+		return err
+	}
+
+	var doc params.Document
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return errors.Wrapf(err, "decode %s", pi.fileName)
+	}
+
+	ns, err := component.GetNamespace(pi.app, doc.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "could not find namespace")
+	}
+
+	var current *params.Document
+	if doc.Environment == "" {
+		current, err = namespaceDocument(pi.app, ns, doc.Namespace)
+	} else {
+		current, err = envDocument(pi.app, ns, doc.Namespace, doc.Environment)
+	}
+	if err != nil {
+		return err
+	}
+
+	targets, err := importTargets(ns)
+	if err != nil {
+		return err
+	}
+
+	for entry, values := range doc.Components {
+		if _, ok := targets[entry]; !ok {
+			return errors.Errorf("component %q does not exist in %s", entry, ns.Name())
+		}
+
+		if err := params.Validate(current.Components[entry], values); err != nil {
+			return errors.Wrapf(err, "component %q", entry)
+		}
+	}
+
+	if err := params.Validate(current.Global, doc.Global); err != nil {
+		return errors.Wrap(err, "global")
+	}
+
+	left := current.Components
+	right := doc.Components
+	if left == nil {
+		left = make(map[string]map[string]interface{})
+	}
+	if right == nil {
+		right = make(map[string]map[string]interface{})
+	}
+	left[globalEntry] = current.Global
+	right[globalEntry] = doc.Global
+
+	var changes []params.Difference
+	for _, d := range params.Diff(left, right) {
+		if d.Status == params.DiffChanged || d.Status == params.DiffRightOnly {
+			changes = append(changes, d)
+		}
+	}
+
+	printImportChanges(changes)
+
+	if pi.dryRun || len(changes) == 0 {
+		return nil
+	}
+
+	if doc.Environment != "" {
+		return updateEnvParams(pi.app, doc.Environment, func(src string) (string, error) {
+			for _, d := range changes {
+				key, root := d.Component, "components"
+				if d.Component == globalEntry {
+					key, root = "", "global"
+				}
+
+				src, err = params.EnvSet(d.Path, src, key, d.Right, root)
+				if err != nil {
+					return "", err
+				}
+			}
+
+			return src, nil
+		})
+	}
+
+	for _, d := range changes {
+		if d.Component == globalEntry {
+			if err := ns.SetParam(d.Path, d.Right); err != nil {
+				return errors.Wrap(err, "set global param")
+			}
+			continue
+		}
+
+		target := targets[d.Component]
+		options := component.ParamOptions{Index: target.index}
+		if err := target.component.SetParam(d.Path, d.Right, options); err != nil {
+			return errors.Wrapf(err, "set param for %s", d.Component)
+		}
+	}
+
+	return nil
+}
+
+// importTargets maps the params entries in a namespace to their components.
+func importTargets(ns component.Namespace) (map[string]importTarget, error) {
+	components, err := ns.Components()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]importTarget)
+	for _, c := range components {
+		entries, err := component.ParamsEntries(c)
+		if err != nil {
+			return nil, err
+		}
+
+		for entry, index := range entries {
+			targets[entry] = importTarget{component: c, index: index}
+		}
+	}
+
+	return targets, nil
+}
+
+func printImportChanges(changes []params.Difference) {
+	table := ksutil.NewTable(os.Stdout)
+	table.SetHeader([]string{"COMPONENT", "KEY", "CURRENT", "IMPORTED"})
+
+	for _, d := range changes {
+		var current string
+		if d.Status == params.DiffChanged {
+			current, _ = component.ParamValue(d.Left)
+		}
+		imported, _ := component.ParamValue(d.Right)
+
+		table.Append([]string{d.Component, d.Key, current, imported})
+	}
+
+	table.Render()
+}
@@ -1,16 +1,22 @@
 package action
 
 import (
+	"encoding/json"
 	"os"
+	"reflect"
+	"sort"
 
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/ksonnet/ksonnet/metadata/app"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
 
-func ParamList(fs afero.Fs, nsName string) error {
-	pl, err := newParamList(fs, nsName)
+func ParamList(fs afero.Fs, nsName string, opts ...ParamListOpt) error {
+	pl, err := newParamList(fs, nsName, opts...)
 	if err != nil {
 		return err
 	}
@@ -18,13 +24,34 @@ func ParamList(fs afero.Fs, nsName string) error {
 	return pl.run()
 }
 
+// ParamListOpt is an option for configuring ParamList.
+type ParamListOpt func(*paramList)
+
+// ParamListWithEnv lists the effective params for an environment.
+func ParamListWithEnv(envName string) ParamListOpt {
+	return func(pl *paramList) {
+		pl.envName = envName
+	}
+}
+
+// ParamListWithReveal decrypts secrets instead of redacting them.
+func ParamListWithReveal(reveal bool) ParamListOpt {
+	return func(pl *paramList) {
+		pl.reveal = reveal
+	}
+}
+
 type paramList struct {
-	nsName string
+	nsName  string
+	envName string
+	reveal  bool
+	// key decrypts secrets if they are revealed.
+	key *params.SecretKey
 
 	*base
 }
 
-func newParamList(fs afero.Fs, nsName string) (*paramList, error) {
+func newParamList(fs afero.Fs, nsName string, opts ...ParamListOpt) (*paramList, error) {
 	b, err := new(fs)
 	if err != nil {
 		return nil, err
@@ -35,6 +62,10 @@ func newParamList(fs afero.Fs, nsName string) (*paramList, error) {
 		base:   b,
 	}
 
+	for _, opt := range opts {
+		opt(pl)
+	}
+
 	return pl, nil
 }
 
@@ -44,19 +75,244 @@ func (pl *paramList) run() error {
 		return errors.Wrap(err, "could not find namespace")
 	}
 
+	if pl.reveal {
+		if pl.key, err = params.LoadSecretKey(pl.app.Fs(), pl.app.Root()); err != nil {
+			return err
+		}
+	}
+
+	if pl.envName != "" {
+		return pl.runEnv(ns)
+	}
+
 	paramData, err := ns.Params()
 	if err != nil {
 		return errors.Wrap(err, "could not list parameters")
 	}
 
+	schemas, err := componentSchemas(pl.app, ns)
+	if err != nil {
+		return err
+	}
+
 	table := ksutil.NewTable(os.Stdout)
 
-	table.SetHeader([]string{"COMPONENT", "INDEX", "KEY", "VALUE"})
-	for _, data := range paramData {
-		table.Append([]string{data.Component, data.Index, data.Key, data.Value})
+	table.SetHeader([]string{"COMPONENT", "INDEX", "KEY", "VALUE", "SOURCE", "DEFAULT", "DESCRIPTION"})
+	for _, data := range withSchemaParams(paramData, schemas) {
+		defaultValue, description, err := schemaDetails(schemas[data.Component], data.Key)
+		if err != nil {
+			return err
+		}
+
+		value, err := params.RevealSecrets(data.Value, pl.key)
+		if err != nil {
+			return errors.Wrapf(err, "reveal %s.%s", data.Component, data.Key)
+		}
+
+		table.Append([]string{data.Component, data.Index, data.Key, value, data.Source,
+			defaultValue, description})
 	}
 
 	table.Render()
 
 	return nil
 }
+
+// componentSchemas loads the params schemas for the components in a
+// namespace.
+func componentSchemas(a app.App, ns component.Namespace) (map[string]*params.Schema, error) {
+	components, err := ns.Components()
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]*params.Schema)
+	for _, c := range components {
+		s, err := component.LoadSchema(a, c)
+		if err != nil {
+			return nil, err
+		}
+
+		if s != nil {
+			schemas[c.Name(false)] = s
+		}
+	}
+
+	return schemas, nil
+}
+
+// withSchemaParams adds the params a schema declares which are not set.
+func withSchemaParams(nsps []component.NamespaceParameter, schemas map[string]*params.Schema) []component.NamespaceParameter {
+	type group struct {
+		component string
+		index     string
+	}
+
+	var groups []group
+	set := make(map[group]map[string]bool)
+	listed := make(map[string]bool)
+	for _, p := range nsps {
+		g := group{component: p.Component, index: p.Index}
+		if _, ok := set[g]; !ok {
+			set[g] = make(map[string]bool)
+			groups = append(groups, g)
+		}
+		set[g][p.Key] = true
+		listed[p.Component] = true
+	}
+
+	for _, name := range sortedKeys(schemas) {
+		if !listed[name] {
+			g := group{component: name, index: "0"}
+			set[g] = make(map[string]bool)
+			groups = append(groups, g)
+		}
+	}
+
+	var out []component.NamespaceParameter
+	for _, g := range groups {
+		for _, p := range nsps {
+			if p.Component == g.component && p.Index == g.index {
+				out = append(out, p)
+			}
+		}
+
+		s, ok := schemas[g.component]
+		if !ok {
+			continue
+		}
+
+		for _, key := range sortedKeys(s.Properties) {
+			if set[g][key] {
+				continue
+			}
+
+			out = append(out, component.NamespaceParameter{
+				Component: g.component,
+				Index:     g.index,
+				Key:       key,
+			})
+		}
+	}
+
+	return out
+}
+
+// schemaDetails returns the default and description the schema declares for
+// a param key. Keys are param paths, unless the schema declares the whole key,
+// e.g. `prometheus.io/scrape`.
+func schemaDetails(s *params.Schema, key string) (string, string, error) {
+	if s == nil {
+		return "", "", nil
+	}
+
+	prop, ok := s.Properties[key]
+	if !ok {
+		var err error
+		if prop, err = s.Lookup(params.SplitPath(key)); err != nil || prop == nil {
+			return "", "", nil
+		}
+	}
+
+	if prop.Default == nil {
+		return "", prop.Description, nil
+	}
+
+	defaultValue, err := component.ParamValue(prop.Default)
+	if err != nil {
+		return "", "", err
+	}
+
+	return defaultValue, prop.Description, nil
+}
+
+// runEnv lists the params for a namespace after the environment's overrides
+// are applied. Secrets stay encrypted until they are shown.
+func (pl *paramList) runEnv(ns component.Namespace) error {
+	if _, err := pl.app.Environment(pl.envName); err != nil {
+		return err
+	}
+
+	sealed := ns.Sealed()
+	resolved, err := sealed.ResolvedParams()
+	if err != nil {
+		return errors.Wrap(err, "resolve namespace parameters")
+	}
+
+	p := pipeline.New(pl.app, pl.envName, pipeline.Sealed())
+	effective, err := p.EnvParameters(ns.Name())
+	if err != nil {
+		return errors.Wrapf(err, "resolve parameters for environment %s", pl.envName)
+	}
+
+	globals, err := ns.Globals()
+	if err != nil {
+		return err
+	}
+
+	base, err := decodeComponentParams(resolved)
+	if err != nil {
+		return err
+	}
+
+	merged, err := decodeComponentParams(effective)
+	if err != nil {
+		return err
+	}
+
+	table := ksutil.NewTable(os.Stdout)
+	table.SetHeader([]string{"COMPONENT", "KEY", "VALUE", "SOURCE"})
+
+	for _, name := range sortedKeys(merged) {
+		for _, key := range sortedKeys(merged[name]) {
+			value := merged[name][key]
+
+			source := ns.Name()
+			if s, ok := globals.Sources[key]; ok {
+				source = s
+			}
+
+			baseValue, ok := base[name][key]
+			if !ok || !reflect.DeepEqual(baseValue, value) {
+				source = pl.envName
+			}
+
+			vStr, err := component.ParamValue(value)
+			if err != nil {
+				return err
+			}
+
+			if vStr, err = params.RevealSecrets(vStr, pl.key); err != nil {
+				return errors.Wrapf(err, "reveal %s.%s", name, key)
+			}
+
+			table.Append([]string{name, key, vStr, source})
+		}
+	}
+
+	table.Render()
+
+	return nil
+}
+
+func decodeComponentParams(s string) (map[string]map[string]interface{}, error) {
+	var params struct {
+		Components map[string]map[string]interface{} `json:"components"`
+	}
+
+	if err := json.Unmarshal([]byte(s), &params); err != nil {
+		return nil, errors.Wrap(err, "decode parameters")
+	}
+
+	return params.Components, nil
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+
+	sort.Strings(keys)
+	return keys
+}
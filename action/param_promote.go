@@ -0,0 +1,148 @@
+package action
+
+import (
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ParamPromote copies environment overrides from one environment to another.
+func ParamPromote(fs afero.Fs, fromEnv, toEnv string, opts ...ParamPromoteOpt) error {
+	pp, err := newParamPromote(fs, fromEnv, toEnv, opts...)
+	if err != nil {
+		return err
+	}
+
+	return pp.Run()
+}
+
+// ParamPromoteOpt is an option for configuring ParamPromote.
+type ParamPromoteOpt func(*paramPromote)
+
+// ParamPromoteWithComponent limits promotion to a component.
+func ParamPromoteWithComponent(name string) ParamPromoteOpt {
+	return func(pp *paramPromote) {
+		if name != "" {
+			pp.components = []string{name}
+		}
+	}
+}
+
+// ParamPromoteWithKeys limits promotion to keys.
+func ParamPromoteWithKeys(keys []string) ParamPromoteOpt {
+	return func(pp *paramPromote) {
+		pp.keys = keys
+	}
+}
+
+// ParamPromoteWithDryRun reports the promotion without writing it.
+func ParamPromoteWithDryRun(dryRun bool) ParamPromoteOpt {
+	return func(pp *paramPromote) {
+		pp.dryRun = dryRun
+	}
+}
+
+// ParamPromoteWithForce overwrites conflicting overrides in the target
+// environment.
+func ParamPromoteWithForce(force bool) ParamPromoteOpt {
+	return func(pp *paramPromote) {
+		pp.force = force
+	}
+}
+
+type paramPromote struct {
+	fromEnv    string
+	toEnv      string
+	components []string
+	keys       []string
+	dryRun     bool
+	force      bool
+
+	*base
+}
+
+func newParamPromote(fs afero.Fs, fromEnv, toEnv string, opts ...ParamPromoteOpt) (*paramPromote, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	pp := &paramPromote{
+		fromEnv: fromEnv,
+		toEnv:   toEnv,
+		base:    b,
+	}
+
+	for _, opt := range opts {
+		opt(pp)
+	}
+
+	return pp, nil
+}
+
+func (pp *paramPromote) Run() error {
+	for _, envName := range []string{pp.fromEnv, pp.toEnv} {
+		if _, err := pp.app.Environment(envName); err != nil {
+			return err
+		}
+	}
+
+	from, err := component.ReadEnvParams(pp.app, pp.fromEnv)
+	if err != nil {
+		return errors.Wrapf(err, "read params for environment %s", pp.fromEnv)
+	}
+
+	to, err := component.ReadEnvParams(pp.app, pp.toEnv)
+	if err != nil {
+		return errors.Wrapf(err, "read params for environment %s", pp.toEnv)
+	}
+
+	promotions, err := params.PlanPromotion(from, to, pp.components, pp.keys)
+	if err != nil {
+		return err
+	}
+
+	pp.print(promotions)
+
+	if pp.dryRun {
+		return nil
+	}
+
+	conflicts := 0
+	for _, p := range promotions {
+		if p.Status == params.PromoteConflict {
+			conflicts++
+		}
+	}
+
+	if conflicts > 0 && !pp.force {
+		return errors.Errorf("%d overrides were changed independently in %s; use --force to overwrite them",
+			conflicts, pp.toEnv)
+	}
+
+	return updateEnvParams(pp.app, pp.toEnv, func(src string) (string, error) {
+		return params.Promote(src, promotions, pp.force)
+	})
+}
+
+func (pp *paramPromote) print(promotions []params.Promotion) {
+	table := ksutil.NewTable(os.Stdout)
+	table.SetHeader([]string{"COMPONENT", "KEY", pp.fromEnv, pp.toEnv, "STATUS"})
+
+	for _, p := range promotions {
+		value, _ := component.ParamValue(p.Value)
+
+		var current string
+		if p.Status != params.PromoteAdd {
+			current, _ = component.ParamValue(p.Current)
+		}
+
+		table.Append([]string{p.Component, p.Key, value, current, string(p.Status)})
+	}
+
+	table.Render()
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
@@ -28,6 +29,27 @@ func ParamSetGlobal(isGlobal bool) ParamSetOpt {
 	}
 }
 
+// ParamSetWithEnv sets the param in an environment's params.
+func ParamSetWithEnv(envName string) ParamSetOpt {
+	return func(paramSet *paramSet) {
+		paramSet.envName = envName
+	}
+}
+
+// ParamSetWithType decodes the value as a type instead of inferring it.
+func ParamSetWithType(valueType string) ParamSetOpt {
+	return func(paramSet *paramSet) {
+		paramSet.valueType = valueType
+	}
+}
+
+// ParamSetWithSecret encrypts the value with the app's secret key.
+func ParamSetWithSecret(isSecret bool) ParamSetOpt {
+	return func(paramSet *paramSet) {
+		paramSet.secret = isSecret
+	}
+}
+
 // ParamSetWithIndex sets the index for the set option.
 func ParamSetWithIndex(index int) ParamSetOpt {
 	return func(paramSet *paramSet) {
@@ -37,11 +59,14 @@ func ParamSetWithIndex(index int) ParamSetOpt {
 
 // ParamSet sets a parameter for a component.
 type paramSet struct {
-	name     string
-	rawPath  string
-	rawValue string
-	index    int
-	global   bool
+	name      string
+	rawPath   string
+	rawValue  string
+	index     int
+	global    bool
+	envName   string
+	valueType string
+	secret    bool
 
 	*base
 }
@@ -69,41 +94,113 @@ func newParamSet(fs afero.Fs, name, path, value string, opts ...ParamSetOpt) (*p
 
 // Run runs the action.
 func (ps *paramSet) Run() error {
-	path := strings.Split(ps.rawPath, ".")
+	path := params.SplitPath(ps.rawPath)
 
-	value, err := params.DecodeValue(ps.rawValue)
+	value, t, err := ps.decodeValue()
 	if err != nil {
 		return errors.Wrap(err, "value is invalid")
 	}
 
+	if ps.envName != "" {
+		return ps.setEnv(path, value, t)
+	}
+
+	if ps.global {
+		return ps.setGlobal(path, value, t)
+	}
+
+	return ps.setLocal(path, value, t)
+}
+
+// decodeValue decodes the value. The type is only returned if it was given
+// explicitly with --type or a prefix, e.g. `int:100`.
+func (ps *paramSet) decodeValue() (interface{}, params.ValueType, error) {
+	s := ps.rawValue
+
+	var t params.ValueType
+	if ps.valueType != "" {
+		var err error
+		if t, err = params.ParseValueType(ps.valueType); err != nil {
+			return nil, "", err
+		}
+	} else if prefixed, v, ok := params.SplitTypedValue(s); ok {
+		t, s = prefixed, v
+	}
+
+	if t == "" {
+		v, err := params.DecodeValue(s)
+		return v, "", err
+	}
+
+	v, err := params.DecodeTypedValue(s, t)
+	return v, t, err
+}
+
+func (ps *paramSet) setEnv(path []string, value interface{}, t params.ValueType) error {
+	key, root := "", "global"
 	if ps.global {
-		return ps.setGlobal(path, value)
+		if ps.name != "" {
+			return errors.New("environment globals apply to all namespaces")
+		}
+	} else {
+		c, err := component.ExtractComponent(ps.app, ps.name)
+		if err != nil {
+			return errors.Wrap(err, "could not find component")
+		}
+
+		if err := validateParam(ps.app, c, path, value); err != nil {
+			return err
+		}
+
+		key, root = component.ParamsEntry(c, ps.index), "components"
+	}
+
+	value, err := ps.seal(value)
+	if err != nil {
+		return err
 	}
 
-	return ps.setLocal(path, value)
+	return updateEnvParams(ps.app, ps.envName, func(src string) (string, error) {
+		return params.EnvSet(path, src, key, value, root, params.SetWithType(t))
+	})
 }
 
-func (ps *paramSet) setGlobal(path []string, value interface{}) error {
+func (ps *paramSet) setGlobal(path []string, value interface{}, t params.ValueType) error {
 	ns, err := component.GetNamespace(ps.app, ps.name)
 	if err != nil {
 		return errors.Wrap(err, "retrieve namespace")
 	}
 
-	if err := ns.SetParam(path, value); err != nil {
+	value, err = ps.seal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.SetParam(path, value, params.SetWithType(t)); err != nil {
 		return errors.Wrap(err, "set global param")
 	}
 
 	return nil
 }
 
-func (ps *paramSet) setLocal(path []string, value interface{}) error {
+func (ps *paramSet) setLocal(path []string, value interface{}, t params.ValueType) error {
 	c, err := component.ExtractComponent(ps.app, ps.name)
 	if err != nil {
 		return errors.Wrap(err, "could not find component")
 	}
 
+	if err := validateParam(ps.app, c, path, value); err != nil {
+		return err
+	}
+
+	value, err = ps.seal(value)
+	if err != nil {
+		return err
+	}
+
 	options := component.ParamOptions{
 		Index: ps.index,
+		Type:  t,
 	}
 	if err := c.SetParam(path, value, options); err != nil {
 		return errors.Wrap(err, "set param")
@@ -111,3 +208,47 @@ func (ps *paramSet) setLocal(path []string, value interface{}) error {
 
 	return nil
 }
+
+// seal encrypts the value if it is a secret. Values are validated before they
+// are sealed.
+func (ps *paramSet) seal(value interface{}) (interface{}, error) {
+	if !ps.secret {
+		return value, nil
+	}
+
+	key, err := params.LoadSecretKey(ps.app.Fs(), ps.app.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := key.Encrypt(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypt secret")
+	}
+
+	return envelope, nil
+}
+
+// validateParam validates a value against the component's params schema.
+func validateParam(a app.App, c component.Component, path []string, value interface{}) error {
+	s, err := component.LoadSchema(a, c)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		return nil
+	}
+
+	schemaErrs := s.ValidateValue(path, value)
+	if len(schemaErrs) == 0 {
+		return nil
+	}
+
+	var msgs []string
+	for _, schemaErr := range schemaErrs {
+		msgs = append(msgs, schemaErr.Error())
+	}
+
+	return errors.Errorf("param does not match schema for %s: %s", c.Name(true), strings.Join(msgs, "; "))
+}
@@ -0,0 +1,180 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/bryanl/woowoo/report"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ReportResources reports the resources requested by an environment.
+func ReportResources(fs afero.Fs, env string, opts ...ReportResourcesOpt) error {
+	rr, err := newReportResources(fs, env, opts...)
+	if err != nil {
+		return err
+	}
+
+	return rr.Run()
+}
+
+// ReportResourcesOpt is an option for configuring ReportResources.
+type ReportResourcesOpt func(*reportResources)
+
+// ReportResourcesWithCompare compares the environment with another
+// environment. The report shows the change from the other environment.
+func ReportResourcesWithCompare(env string) ReportResourcesOpt {
+	return func(rr *reportResources) {
+		rr.compareEnv = env
+	}
+}
+
+// ReportResourcesWithOutput sets the output format. Valid formats are
+// `table` and `json`.
+func ReportResourcesWithOutput(output string) ReportResourcesOpt {
+	return func(rr *reportResources) {
+		rr.output = output
+	}
+}
+
+type reportResources struct {
+	env        string
+	compareEnv string
+	output     string
+
+	*base
+}
+
+func newReportResources(fs afero.Fs, env string, opts ...ReportResourcesOpt) (*reportResources, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	rr := &reportResources{
+		env:    env,
+		output: "table",
+		base:   b,
+	}
+
+	for _, opt := range opts {
+		opt(rr)
+	}
+
+	return rr, nil
+}
+
+// Run runs the action.
+func (rr *reportResources) Run() error {
+	if rr.output != "table" && rr.output != "json" {
+		return errors.Errorf("unknown output format %q", rr.output)
+	}
+
+	r, err := rr.report(rr.env)
+	if err != nil {
+		return err
+	}
+
+	if rr.compareEnv == "" {
+		if rr.output == "json" {
+			return printReportJSON(r)
+		}
+
+		printResourceReport(r, quantityString)
+		return nil
+	}
+
+	from, err := rr.report(rr.compareEnv)
+	if err != nil {
+		return err
+	}
+
+	delta := report.Compare(from, r)
+
+	if rr.output == "json" {
+		return printReportJSON(map[string]interface{}{
+			"from":  from,
+			"to":    r,
+			"delta": delta,
+		})
+	}
+
+	fmt.Printf("change from %s to %s\n\n", rr.compareEnv, rr.env)
+	printResourceReport(delta, deltaString)
+	return nil
+}
+
+func (rr *reportResources) report(env string) (*report.ResourceReport, error) {
+	p := pipeline.New(rr.app, env)
+
+	rendered, err := p.ComponentObjects(nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "render environment %s", env)
+	}
+
+	return report.NewResourceReport(env, rendered)
+}
+
+func printReportJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printResourceReport(r *report.ResourceReport, format func(resource.Quantity) string) {
+	row := func(scope, name string, res report.Resources) []string {
+		return []string{
+			scope,
+			name,
+			format(res.Requests.CPU),
+			format(res.Limits.CPU),
+			format(res.Requests.Memory),
+			format(res.Limits.Memory),
+			format(res.Requests.EphemeralStorage),
+			format(res.Limits.EphemeralStorage),
+		}
+	}
+
+	table := ksutil.NewTable(os.Stdout)
+	table.SetHeader([]string{"scope", "name",
+		"cpu requests", "cpu limits",
+		"memory requests", "memory limits",
+		"storage requests", "storage limits"})
+
+	for _, u := range r.Components {
+		table.Append(row("component", u.Name, u.Resources))
+	}
+	for _, u := range r.Namespaces {
+		table.Append(row("namespace", u.Name, u.Resources))
+	}
+	table.Append(row("environment", r.Environment, r.Total))
+
+	table.Render()
+}
+
+func quantityString(q resource.Quantity) string {
+	if q.IsZero() {
+		return "-"
+	}
+
+	return q.String()
+}
+
+func deltaString(q resource.Quantity) string {
+	if q.IsZero() {
+		return "-"
+	}
+
+	s := q.String()
+	if !strings.HasPrefix(s, "-") {
+		s = "+" + s
+	}
+
+	return s
+}
@@ -1,9 +1,9 @@
 package action
 
 import (
-	"io"
 	"os"
 
+	"github.com/bryanl/woowoo/ksutil"
 	"github.com/bryanl/woowoo/pipeline"
 	"github.com/spf13/afero"
 )
@@ -28,10 +28,29 @@ func ShowWithComponents(names ...string) ShowOpt {
 	}
 }
 
+// ShowWithFormat sets the output format. See ksutil.Fprint for valid formats.
+func ShowWithFormat(format string) ShowOpt {
+	return func(s *show) {
+		s.format = format
+	}
+}
+
+// ShowWithOutputDir writes the rendered objects to a directory tree instead
+// of stdout.
+func ShowWithOutputDir(dir string, kustomization bool) ShowOpt {
+	return func(s *show) {
+		s.outputDir = dir
+		s.kustomization = kustomization
+	}
+}
+
 // Show is a show Action
 type show struct {
-	env        string
-	components []string
+	env           string
+	components    []string
+	format        string
+	outputDir     string
+	kustomization bool
 
 	*base
 }
@@ -44,8 +63,9 @@ func newShow(fs afero.Fs, env string, opts ...ShowOpt) (*show, error) {
 	}
 
 	s := &show{
-		env:  env,
-		base: b,
+		env:    env,
+		format: "yaml",
+		base:   b,
 	}
 
 	for _, opt := range opts {
@@ -59,11 +79,26 @@ func newShow(fs afero.Fs, env string, opts ...ShowOpt) (*show, error) {
 func (s *show) Run() error {
 	p := pipeline.New(s.app, s.env)
 
-	data, err := p.YAML(s.components)
+	if s.outputDir != "" {
+		return s.writeDir(p)
+	}
+
+	objects, err := p.Objects(s.components)
+	if err != nil {
+		return err
+	}
+
+	return ksutil.Fprint(os.Stdout, objects, s.format)
+}
+
+func (s *show) writeDir(p *pipeline.Pipeline) error {
+	rendered, err := p.ComponentObjects(s.components)
 	if err != nil {
 		return err
 	}
 
-	_, err = io.Copy(os.Stdout, data)
-	return err
+	dw := pipeline.NewDirWriter(s.app.Fs(), s.outputDir,
+		pipeline.DirWriterWithKustomization(s.kustomization),
+		pipeline.DirWriterWithPartial(len(s.components) > 0))
+	return dw.Write(rendered)
 }
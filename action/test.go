@@ -0,0 +1,161 @@
+package action
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// testsDir is the directory which contains golden files.
+	testsDir = "tests"
+)
+
+// Test renders environments and compares them to their golden files.
+func Test(fs afero.Fs, envNames []string, opts ...TestOpt) error {
+	et, err := newEnvTest(fs, envNames, opts...)
+	if err != nil {
+		return err
+	}
+
+	return et.Run()
+}
+
+// TestOpt is an option for configuring Test.
+type TestOpt func(*envTest)
+
+// TestWithUpdate rewrites the golden files instead of comparing them.
+func TestWithUpdate(update bool) TestOpt {
+	return func(et *envTest) {
+		et.update = update
+	}
+}
+
+type envTest struct {
+	envNames []string
+	update   bool
+	out      io.Writer
+
+	*base
+}
+
+func newEnvTest(fs afero.Fs, envNames []string, opts ...TestOpt) (*envTest, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	et := &envTest{
+		envNames: envNames,
+		out:      os.Stdout,
+		base:     b,
+	}
+
+	for _, opt := range opts {
+		opt(et)
+	}
+
+	return et, nil
+}
+
+// Run runs the action.
+func (et *envTest) Run() error {
+	envNames := et.envNames
+	if len(envNames) == 0 {
+		environments, err := et.app.Environments()
+		if err != nil {
+			return err
+		}
+
+		for name := range environments {
+			envNames = append(envNames, name)
+		}
+		sort.Strings(envNames)
+	}
+
+	var failed int
+	for _, envName := range envNames {
+		ok, err := et.testEnv(envName)
+		if err != nil {
+			return errors.Wrapf(err, "test environment %q", envName)
+		}
+
+		if !ok {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d environment(s) did not match their golden files", failed)
+	}
+
+	return nil
+}
+
+func (et *envTest) testEnv(envName string) (bool, error) {
+	p := pipeline.New(et.app, envName)
+
+	r, err := p.YAML(nil)
+	if err != nil {
+		return false, err
+	}
+
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		return false, err
+	}
+
+	goldenPath := filepath.Join(et.app.Root(), testsDir, envName+".yaml")
+	relPath := filepath.Join(testsDir, envName+".yaml")
+
+	if et.update {
+		if err = et.app.Fs().MkdirAll(filepath.Dir(goldenPath), app.DefaultFolderPermissions); err != nil {
+			return false, err
+		}
+
+		if err = afero.WriteFile(et.app.Fs(), goldenPath, got, app.DefaultFilePermissions); err != nil {
+			return false, err
+		}
+
+		fmt.Fprintf(et.out, "updated %s\n", relPath)
+		return true, nil
+	}
+
+	exists, err := afero.Exists(et.app.Fs(), goldenPath)
+	if err != nil {
+		return false, err
+	}
+
+	if !exists {
+		fmt.Fprintf(et.out, "FAIL %s: %s does not exist (run with --update to create it)\n", envName, relPath)
+		return false, nil
+	}
+
+	expected, err := afero.ReadFile(et.app.Fs(), goldenPath)
+	if err != nil {
+		return false, err
+	}
+
+	diff, err := ksutil.Diff(string(expected), string(got), relPath, envName)
+	if err != nil {
+		return false, err
+	}
+
+	if diff == "" {
+		fmt.Fprintf(et.out, "ok   %s\n", envName)
+		return true, nil
+	}
+
+	fmt.Fprintf(et.out, "FAIL %s\n", envName)
+	fmt.Fprint(et.out, diff)
+	return false, nil
+}
@@ -0,0 +1,116 @@
+package action
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const testGolden = `---
+apiVersion: v1
+data:
+  replicas: "1"
+kind: ConfigMap
+metadata:
+  name: web
+`
+
+func TestTest(t *testing.T) {
+	cases := []struct {
+		name     string
+		golden   string
+		update   bool
+		isErr    bool
+		contains []string
+	}{
+		{
+			name:     "pass",
+			golden:   testGolden,
+			contains: []string{"ok   default"},
+		},
+		{
+			name: "mismatch",
+			golden: `---
+apiVersion: v1
+data:
+  replicas: "2"
+kind: ConfigMap
+metadata:
+  name: web
+`,
+			isErr:    true,
+			contains: []string{"FAIL default", `-  replicas: "2"`, `+  replicas: "1"`},
+		},
+		{
+			name:     "missing golden",
+			isErr:    true,
+			contains: []string{"tests/default.yaml does not exist"},
+		},
+		{
+			name:     "update",
+			golden:   "stale\n",
+			update:   true,
+			contains: []string{"updated tests/default.yaml"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, fs := appMock("/app")
+			a.On("Environment", "default").Return(&app.EnvironmentSpec{}, nil)
+
+			writeFile(t, fs, "/app/components/params.libsonnet", `{
+  global: {},
+  components: {
+    web: {
+      replicas: "1",
+    },
+  },
+}
+`)
+			writeFile(t, fs, "/app/components/web.jsonnet", `local params = std.extVar("__ksonnet/params").components.web;
+
+{
+  apiVersion: "v1",
+  kind: "ConfigMap",
+  metadata: {name: "web"},
+  data: {replicas: params.replicas},
+}
+`)
+			writeFile(t, fs, "/app/lib/v1.8.7/k.libsonnet", `{}`)
+			writeFile(t, fs, "/app/lib/v1.8.7/k8s.libsonnet", `{}`)
+			writeFile(t, fs, "/app/environments/default/params.libsonnet", `std.extVar("__ksonnet/params")`)
+			if tc.golden != "" {
+				writeFile(t, fs, "/app/tests/default.yaml", tc.golden)
+			}
+
+			var buf bytes.Buffer
+			et := &envTest{
+				envNames: []string{"default"},
+				update:   tc.update,
+				out:      &buf,
+				base:     &base{app: a},
+			}
+
+			err := et.Run()
+			if tc.isErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for _, s := range tc.contains {
+				require.Contains(t, buf.String(), s)
+			}
+
+			if tc.update {
+				got, err := afero.ReadFile(fs, "/app/tests/default.yaml")
+				require.NoError(t, err)
+				require.Equal(t, testGolden, string(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bryanl/woowoo/unittest"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// TestUnit runs the jsonnet unit tests for components.
+func TestUnit(fs afero.Fs, envName string) error {
+	tu, err := newTestUnit(fs, envName)
+	if err != nil {
+		return err
+	}
+
+	return tu.Run()
+}
+
+type testUnit struct {
+	envName string
+
+	*base
+}
+
+func newTestUnit(fs afero.Fs, envName string) (*testUnit, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	tu := &testUnit{
+		envName: envName,
+		base:    b,
+	}
+
+	return tu, nil
+}
+
+// Run runs the action.
+func (tu *testUnit) Run() error {
+	runner := unittest.NewRunner(tu.app, tu.envName)
+
+	files, err := runner.Files()
+	if err != nil {
+		return err
+	}
+
+	var passed, failed int
+	for _, file := range files {
+		results, err := runner.Run(file)
+		if err != nil {
+			return errors.Wrapf(err, "run tests in %s", file)
+		}
+
+		for _, result := range results {
+			name := result.File
+			if result.Name != "" {
+				name = fmt.Sprintf("%s %s", result.File, result.Name)
+			}
+
+			if result.Passed() {
+				passed++
+				fmt.Printf("ok   %s\n", name)
+				continue
+			}
+
+			failed++
+			fmt.Printf("FAIL %s\n", name)
+			fmt.Println(indent(result.Err.Error(), "    "))
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d failed\n", passed, failed)
+
+	if failed > 0 {
+		return errors.Errorf("%d test(s) failed", failed)
+	}
+
+	return nil
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+
+	return strings.Join(lines, "\n")
+}
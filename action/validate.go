@@ -0,0 +1,140 @@
+package action
+
+import (
+	"os"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Validate validates the effective params of each component in an
+// environment against the component's params schema.
+func Validate(fs afero.Fs, env string) error {
+	v, err := newValidate(fs, env)
+	if err != nil {
+		return err
+	}
+
+	return v.Run()
+}
+
+type validate struct {
+	env string
+
+	*base
+}
+
+func newValidate(fs afero.Fs, env string) (*validate, error) {
+	b, err := new(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &validate{
+		env:  env,
+		base: b,
+	}
+
+	return v, nil
+}
+
+// Run runs the action.
+func (v *validate) Run() error {
+	if _, err := v.app.Environment(v.env); err != nil {
+		return err
+	}
+
+	p := pipeline.New(v.app, v.env)
+
+	components, err := p.Components(nil)
+	if err != nil {
+		return err
+	}
+
+	envGlobals, err := v.envGlobals()
+	if err != nil {
+		return err
+	}
+
+	// effective params and globals are resolved once per namespace
+	nsParams := make(map[string]map[string]map[string]interface{})
+	nsGlobals := make(map[string]map[string]interface{})
+
+	table := ksutil.NewTable(os.Stdout)
+	table.SetHeader([]string{"component", "param", "message"})
+
+	invalid := 0
+	for _, c := range components {
+		s, err := component.LoadSchema(v.app, c)
+		if err != nil {
+			return err
+		}
+
+		if s == nil {
+			continue
+		}
+
+		ns, _ := component.ExtractNamespacedComponent(v.app, c.Name(true))
+		effective, ok := nsParams[ns.Name()]
+		if !ok {
+			paramsStr, err := p.EnvParameters(ns.Name())
+			if err != nil {
+				return err
+			}
+
+			effective, err = decodeComponentParams(paramsStr)
+			if err != nil {
+				return err
+			}
+
+			nsParams[ns.Name()] = effective
+
+			gp, err := ns.Globals()
+			if err != nil {
+				return err
+			}
+
+			gp.Merge(v.env, envGlobals)
+			nsGlobals[ns.Name()] = gp.Values
+		}
+
+		entries, err := component.ParamsEntries(c)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range sortedKeys(entries) {
+			for _, schemaErr := range s.Validate(effective[entry], nsGlobals[ns.Name()]) {
+				table.Append([]string{c.Name(true), schemaErr.Path, schemaErr.Message})
+				invalid++
+			}
+		}
+	}
+
+	if invalid == 0 {
+		return nil
+	}
+
+	table.Render()
+
+	return errors.Errorf("environment %q has %d invalid params", v.env, invalid)
+}
+
+// envGlobals returns the environment's global params.
+func (v *validate) envGlobals() (map[string]interface{}, error) {
+	src, err := component.ReadEnvParams(v.app, v.env)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := params.EnvHasRoot(src, "global")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return params.EnvToMap("", src, "global")
+}
@@ -0,0 +1,106 @@
+package action
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bryanl/woowoo/ksutil/mocks"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const validateSchema = `{
+  "properties": {
+    "image": {"type": "string"},
+    "replicas": {"type": "integer"}
+  }
+}`
+
+func TestValidate_globals(t *testing.T) {
+	cases := []struct {
+		name      string
+		envParams string
+		isErr     bool
+	}{
+		{
+			name: "globals",
+			envParams: `local params = std.extVar("__ksonnet/params");
+
+params + {
+  components+: {
+    web+: {
+      replicas: 2,
+    },
+  },
+  global+: {
+    cluster: "prod",
+  },
+}
+`,
+		},
+		{
+			name: "invalid param",
+			envParams: `local params = std.extVar("__ksonnet/params");
+
+params + {
+  components+: {
+    web+: {
+      replicas: "2",
+    },
+  },
+}
+`,
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, fs := appMock("/app")
+			a.On("Environment", "default").Return(&app.EnvironmentSpec{}, nil)
+
+			writeFile(t, fs, "/app/components/params.libsonnet", `{
+  global: {
+    registry: "gcr.io/example",
+  },
+  components: {
+    web: {
+      image: "web:1",
+      replicas: 1,
+    },
+  },
+}
+`)
+			writeFile(t, fs, "/app/components/web.jsonnet", `{}`)
+			writeFile(t, fs, "/app/components/web.schema.json", validateSchema)
+			writeFile(t, fs, "/app/environments/default/params.libsonnet", tc.envParams)
+
+			v := &validate{env: "default", base: &base{app: a}}
+
+			err := v.Run()
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func appMock(root string) (*mocks.SuperApp, afero.Fs) {
+	fs := afero.NewMemMapFs()
+	a := &mocks.SuperApp{}
+	a.On("Fs").Return(fs)
+	a.On("Root").Return(root)
+	a.On("LibPath", mock.AnythingOfType("string")).Return(filepath.Join(root, "lib", "v1.8.7"), nil)
+
+	return a, fs
+}
+
+func writeFile(t *testing.T, fs afero.Fs, path, content string) {
+	require.NoError(t, fs.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+}
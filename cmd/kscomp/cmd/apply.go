@@ -13,6 +13,8 @@ const (
 	vApplyDryRun = "apply-dru-run"
 	vApplyGcTag  = "apply-gc-tag"
 	vApplySkipGc = "apply-skip-gc"
+
+	vApplySkipPolicies = "apply-skip-policies"
 )
 
 var (
@@ -39,7 +41,8 @@ var applyCmd = &cobra.Command{
 			Client: applyClientConfig,
 		}
 
-		return action.Apply(fs, env, options)
+		skipPolicies := action.ApplyWithSkipPolicies(viper.GetBool(vApplySkipPolicies))
+		return action.Apply(fs, env, options, skipPolicies)
 	},
 }
 
@@ -60,4 +63,7 @@ func init() {
 
 	applyCmd.Flags().Bool(flagDryRun, false, "Option to preview the list of operations without changing the cluster state")
 	viper.BindPFlag(vApplyDryRun, applyCmd.Flags().Lookup(flagDryRun))
+
+	applyCmd.Flags().Bool(flagSkipPolicies, false, "Option to apply even if policies report errors")
+	viper.BindPFlag(vApplySkipPolicies, applyCmd.Flags().Lookup(flagSkipPolicies))
 }
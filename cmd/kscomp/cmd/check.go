@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check <environment>",
+	Short: "check an environment against policies",
+	Long: `Check the objects rendered for an environment against the policies in
+policies/*.jsonnet. Violations with error severity fail the check.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("check <environment>")
+		}
+
+		return action.Check(fs, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
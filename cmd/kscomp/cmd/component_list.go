@@ -7,6 +7,7 @@ import (
 )
 
 const (
+	vComponentListEnv       = "component-list-env"
 	vComponentListNamespace = "component-list-ns"
 	vComponentListOutput    = "component-list-output"
 )
@@ -18,7 +19,8 @@ var componentListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		namespace := viper.GetString(vComponentListNamespace)
 		output := viper.GetString(vComponentListOutput)
-		return action.ComponentList(fs, namespace, output)
+		env := viper.GetString(vComponentListEnv)
+		return action.ComponentList(fs, namespace, output, action.ComponentListWithEnv(env))
 	},
 }
 
@@ -28,6 +30,9 @@ func init() {
 	componentListCmd.Flags().String(flagNamespace, "", "Component namespace")
 	viper.BindPFlag(vComponentListNamespace, componentListCmd.Flags().Lookup(flagNamespace))
 
+	componentListCmd.Flags().String(flagEnv, "", "List components as seen by an environment and mark environment overrides")
+	viper.BindPFlag(vComponentListEnv, componentListCmd.Flags().Lookup(flagEnv))
+
 	componentListCmd.Flags().StringP(flagOutput, "o", "", "Output format. Valid options: wide")
 	viper.BindPFlag(vComponentListOutput, componentListCmd.Flags().Lookup(flagOutput))
 }
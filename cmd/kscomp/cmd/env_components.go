@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// envComponentsCmd represents the env components command
+var envComponentsCmd = &cobra.Command{
+	Use:   "components",
+	Short: "components",
+	Long:  `components`,
+}
+
+func init() {
+	envCmd.AddCommand(envComponentsCmd)
+}
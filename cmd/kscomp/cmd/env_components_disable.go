@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// envComponentsDisableCmd represents the env components disable command
+var envComponentsDisableCmd = &cobra.Command{
+	Use:   "disable <environment> <component>",
+	Short: "disable",
+	Long:  `disable`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("env components disable <environment> <component>")
+		}
+
+		environment := args[0]
+		componentName := args[1]
+
+		return action.EnvComponentsDisable(fs, environment, componentName)
+	},
+}
+
+func init() {
+	envComponentsCmd.AddCommand(envComponentsDisableCmd)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// envComponentsEnableCmd represents the env components enable command
+var envComponentsEnableCmd = &cobra.Command{
+	Use:   "enable <environment> <component>",
+	Short: "enable",
+	Long:  `enable`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("env components enable <environment> <component>")
+		}
+
+		environment := args[0]
+		componentName := args[1]
+
+		return action.EnvComponentsEnable(fs, environment, componentName)
+	},
+}
+
+func init() {
+	envComponentsCmd.AddCommand(envComponentsEnableCmd)
+}
@@ -9,6 +9,18 @@ var (
 	flagOutput    = "output"
 	flagVerbose   = "verbose"
 
+	flagAll           = "all"
+	flagCompare       = "compare"
+	flagForce         = "force"
+	flagKeys          = "keys"
+	flagKustomization = "kustomization"
+	flagOutputDir     = "output-dir"
+	flagReveal        = "reveal"
+	flagSecret        = "secret"
+	flagSkipPolicies  = "skip-policies"
+	flagType          = "type"
+	flagUpdate        = "update"
+
 	// these are on loan from the ksonnet app
 	flagGracePeriod = "grace-period"
 	flagCreate      = "create"
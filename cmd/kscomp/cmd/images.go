@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// imagesCmd represents the images command
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "images",
+	Long:  `images`,
+}
+
+func init() {
+	rootCmd.AddCommand(imagesCmd)
+}
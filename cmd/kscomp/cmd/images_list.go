@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// imagesListCmd represents the images list command
+var imagesListCmd = &cobra.Command{
+	Use:   "list <environment>",
+	Short: "list container images in an environment",
+	Long: `List the images of every container and init container rendered for an
+environment, with the component which renders it and the param which sets it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("images list <environment>")
+		}
+
+		return action.ImagesList(fs, args[0])
+	},
+}
+
+func init() {
+	imagesCmd.AddCommand(imagesListCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vImagesSetDryRun = "images-set-dry-run"
+)
+
+// imagesSetCmd represents the images set command
+var imagesSetCmd = &cobra.Command{
+	Use:   "set <repository> <tag>",
+	Short: "set the tag for an image repository",
+	Long: `Set the tag for an image repository in every component param and global
+param which references the repository.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("images set <repository> <tag>")
+		}
+
+		dryRun := viper.GetBool(vImagesSetDryRun)
+
+		return action.ImagesSet(fs, args[0], args[1],
+			action.ImagesSetWithDryRun(dryRun))
+	},
+}
+
+func init() {
+	imagesCmd.AddCommand(imagesSetCmd)
+
+	imagesSetCmd.Flags().Bool(flagDryRun, false, "Show the changes without writing them")
+	viper.BindPFlag(vImagesSetDryRun, imagesSetCmd.Flags().Lookup(flagDryRun))
+}
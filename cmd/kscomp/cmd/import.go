@@ -47,7 +47,7 @@ var importCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(importCmd)
 
-	importCmd.Flags().StringP(flagFilename, "f", "", "Filename or directory for component to import")
+	importCmd.Flags().StringP(flagFilename, "f", "", "Filename, directory, or unpacked Helm chart directory for component to import")
 	viper.BindPFlag(vImportFilename, importCmd.Flags().Lookup(flagFilename))
 	importCmd.Flags().String(flagNamespace, "", "Component namespace")
 	viper.BindPFlag(vImportNamespace, importCmd.Flags().Lookup(flagNamespace))
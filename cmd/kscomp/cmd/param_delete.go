@@ -23,20 +23,25 @@ import (
 
 const (
 	vParamDeleteIndex = "param-delete-index"
+	vParamDeleteEnv   = "param-delete-env"
 )
 
 // deleteCmd represents the delete command
 var paramDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "delete param",
-	Long:  `delete param`,
+	Long: `delete param
+
+Deleting a list element, e.g. ports[1] or ports[name=http], removes it from the
+list.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 2 {
 			logrus.Fatal("delete <component-name> <param-key> ")
 		}
 
 		indexOpt := action.ParamDeleteWithIndex(viper.GetInt(vParamDeleteIndex))
-		return action.ParamDelete(fs, args[0], args[1], indexOpt)
+		envOpt := action.ParamDeleteWithEnv(viper.GetString(vParamDeleteEnv))
+		return action.ParamDelete(fs, args[0], args[1], indexOpt, envOpt)
 	},
 }
 
@@ -45,4 +50,7 @@ func init() {
 
 	paramDeleteCmd.Flags().IntP(flagIndex, "i", 0, "Index in manifest")
 	viper.BindPFlag(vParamDeleteIndex, paramDeleteCmd.Flags().Lookup(flagIndex))
+
+	paramDeleteCmd.Flags().String(flagEnv, "", "Environment to delete the param from")
+	viper.BindPFlag(vParamDeleteEnv, paramDeleteCmd.Flags().Lookup(flagEnv))
 }
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamDiffAll    = "param-diff-all"
+	vParamDiffReveal = "param-diff-reveal"
+)
+
+var paramDiffCmd = &cobra.Command{
+	Use:   "diff <env1> <env2> [namespace]",
+	Short: "param diff",
+	Long: `param diff
+
+Secret values are redacted unless --reveal is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var nsName string
+		switch len(args) {
+		case 2:
+		case 3:
+			nsName = args[2]
+		default:
+			return errors.New("diff <env1> <env2> [namespace]")
+		}
+
+		allOpt := action.ParamDiffWithAll(viper.GetBool(vParamDiffAll))
+		revealOpt := action.ParamDiffWithReveal(viper.GetBool(vParamDiffReveal))
+		return action.ParamDiff(fs, args[0], args[1], nsName, allOpt, revealOpt)
+	},
+}
+
+func init() {
+	paramCmd.AddCommand(paramDiffCmd)
+
+	paramDiffCmd.Flags().Bool(flagAll, false, "Show params which are equal")
+	viper.BindPFlag(vParamDiffAll, paramDiffCmd.Flags().Lookup(flagAll))
+
+	paramDiffCmd.Flags().Bool(flagReveal, false, "Show decrypted secret values")
+	viper.BindPFlag(vParamDiffReveal, paramDiffCmd.Flags().Lookup(flagReveal))
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamExportEnv    = "param-export-env"
+	vParamExportOutput = "param-export-output"
+)
+
+var paramExportCmd = &cobra.Command{
+	Use:   "export [namespace]",
+	Short: "param export",
+	Long: `Export the component and global params for a namespace as a YAML or JSON
+document. With --env, the environment's effective params are exported.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var nsName string
+		switch len(args) {
+		case 0:
+		case 1:
+			nsName = args[0]
+		default:
+			return errors.New("export [namespace]")
+		}
+
+		return action.ParamExport(fs, nsName,
+			action.ParamExportWithEnv(viper.GetString(vParamExportEnv)),
+			action.ParamExportWithOutput(viper.GetString(vParamExportOutput)))
+	},
+}
+
+func init() {
+	paramCmd.AddCommand(paramExportCmd)
+
+	paramExportCmd.Flags().String(flagEnv, "", "Environment to export effective params for")
+	viper.BindPFlag(vParamExportEnv, paramExportCmd.Flags().Lookup(flagEnv))
+
+	paramExportCmd.Flags().StringP(flagOutput, "o", "yaml", "Output format. Valid options: yaml, json")
+	viper.BindPFlag(vParamExportOutput, paramExportCmd.Flags().Lookup(flagOutput))
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamImportDryRun = "param-import-dry-run"
+)
+
+var paramImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "param import",
+	Long: `Import params from a YAML or JSON document created by param export. The
+changes are reported before they are written.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("import <file>")
+		}
+
+		return action.ParamImport(fs, args[0],
+			action.ParamImportWithDryRun(viper.GetBool(vParamImportDryRun)))
+	},
+}
+
+func init() {
+	paramCmd.AddCommand(paramImportCmd)
+
+	paramImportCmd.Flags().Bool(flagDryRun, false, "Show the changes without writing them")
+	viper.BindPFlag(vParamImportDryRun, paramImportCmd.Flags().Lookup(flagDryRun))
+}
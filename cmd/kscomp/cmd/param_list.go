@@ -22,16 +22,22 @@ import (
 
 const (
 	vParamListNamespace = "param-list-ns"
+	vParamListEnv       = "param-list-env"
+	vParamListReveal    = "param-list-reveal"
 )
 
 // listCmd represents the list command
 var paramListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "param list",
-	Long:  `param list`,
+	Long: `param list
+
+Secret values are redacted unless --reveal is given.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		nsName := viper.GetString(vParamListNamespace)
-		return action.ParamList(fs, nsName)
+		envOpt := action.ParamListWithEnv(viper.GetString(vParamListEnv))
+		revealOpt := action.ParamListWithReveal(viper.GetBool(vParamListReveal))
+		return action.ParamList(fs, nsName, envOpt, revealOpt)
 	},
 }
 
@@ -40,4 +46,10 @@ func init() {
 
 	paramListCmd.Flags().String(flagNamespace, "", "Component namespace")
 	viper.BindPFlag(vParamListNamespace, paramListCmd.Flags().Lookup(flagNamespace))
+
+	paramListCmd.Flags().String(flagEnv, "", "Environment to list effective params for")
+	viper.BindPFlag(vParamListEnv, paramListCmd.Flags().Lookup(flagEnv))
+
+	paramListCmd.Flags().Bool(flagReveal, false, "Show decrypted secret values")
+	viper.BindPFlag(vParamListReveal, paramListCmd.Flags().Lookup(flagReveal))
 }
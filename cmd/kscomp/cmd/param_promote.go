@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamPromoteComponent = "param-promote-component"
+	vParamPromoteKeys      = "param-promote-keys"
+	vParamPromoteDryRun    = "param-promote-dry-run"
+	vParamPromoteForce     = "param-promote-force"
+)
+
+var paramPromoteCmd = &cobra.Command{
+	Use:   "promote <from-env> <to-env>",
+	Short: "param promote",
+	Long: `Copy environment param overrides from one environment to another.
+Overrides which were changed independently in the target environment are
+reported as conflicts.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.New("promote <from-env> <to-env>")
+		}
+
+		return action.ParamPromote(fs, args[0], args[1],
+			action.ParamPromoteWithComponent(viper.GetString(vParamPromoteComponent)),
+			action.ParamPromoteWithKeys(viper.GetStringSlice(vParamPromoteKeys)),
+			action.ParamPromoteWithDryRun(viper.GetBool(vParamPromoteDryRun)),
+			action.ParamPromoteWithForce(viper.GetBool(vParamPromoteForce)))
+	},
+}
+
+func init() {
+	paramCmd.AddCommand(paramPromoteCmd)
+
+	paramPromoteCmd.Flags().String(flagComponent, "", "Component to promote")
+	viper.BindPFlag(vParamPromoteComponent, paramPromoteCmd.Flags().Lookup(flagComponent))
+
+	paramPromoteCmd.Flags().StringSlice(flagKeys, nil, "Keys to promote")
+	viper.BindPFlag(vParamPromoteKeys, paramPromoteCmd.Flags().Lookup(flagKeys))
+
+	paramPromoteCmd.Flags().Bool(flagDryRun, false, "Show the promotion without writing it")
+	viper.BindPFlag(vParamPromoteDryRun, paramPromoteCmd.Flags().Lookup(flagDryRun))
+
+	paramPromoteCmd.Flags().Bool(flagForce, false, "Overwrite conflicting overrides")
+	viper.BindPFlag(vParamPromoteForce, paramPromoteCmd.Flags().Lookup(flagForce))
+}
@@ -22,21 +22,35 @@ import (
 )
 
 const (
-	vParamSetIndex = "param-set-index"
+	vParamSetIndex  = "param-set-index"
+	vParamSetEnv    = "param-set-env"
+	vParamSetType   = "param-set-type"
+	vParamSetSecret = "param-set-secret"
 )
 
 // setCmd represents the set command
 var paramSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "param set",
-	Long:  `param set`,
+	Long: `param set
+
+Param keys are dotted paths. List elements are selected by index, e.g.
+containers[1].image, or by the value of a key, e.g. ports[name=http].port.
+Set ports[+] to append an element to a list.
+
+Secret values are encrypted with the key in $KSCOMP_SECRET_KEY, or in the
+file named by $KSCOMP_SECRET_KEY_FILE, or .secret.key in the app root. Keys
+are 32 random bytes encoded as base64.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 3 {
 			logrus.Fatal("set <component-name> <param-key> <param-value>")
 		}
 
 		indexOpt := action.ParamSetWithIndex(viper.GetInt(vParamSetIndex))
-		return action.ParamSet(fs, args[0], args[1], args[2], indexOpt)
+		envOpt := action.ParamSetWithEnv(viper.GetString(vParamSetEnv))
+		typeOpt := action.ParamSetWithType(viper.GetString(vParamSetType))
+		secretOpt := action.ParamSetWithSecret(viper.GetBool(vParamSetSecret))
+		return action.ParamSet(fs, args[0], args[1], args[2], indexOpt, envOpt, typeOpt, secretOpt)
 	},
 }
 
@@ -46,5 +60,12 @@ func init() {
 	paramSetCmd.Flags().IntP(flagIndex, "i", 0, "Index in manifest")
 	viper.BindPFlag(vParamSetIndex, paramSetCmd.Flags().Lookup(flagIndex))
 
-	// TODO: support env
+	paramSetCmd.Flags().String(flagEnv, "", "Environment to set the param in")
+	viper.BindPFlag(vParamSetEnv, paramSetCmd.Flags().Lookup(flagEnv))
+
+	paramSetCmd.Flags().String(flagType, "", "Type of the value. It can change the type of the current value. Valid options: string, int, float, bool, json")
+	viper.BindPFlag(vParamSetType, paramSetCmd.Flags().Lookup(flagType))
+
+	paramSetCmd.Flags().Bool(flagSecret, false, "Encrypt the value")
+	viper.BindPFlag(vParamSetSecret, paramSetCmd.Flags().Lookup(flagSecret))
 }
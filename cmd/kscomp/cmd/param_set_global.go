@@ -4,6 +4,11 @@ import (
 	"github.com/bryanl/woowoo/action"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vParamSetGlobalEnv = "param-set-global-env"
 )
 
 var paramSetGlobalCmd = &cobra.Command{
@@ -25,12 +30,14 @@ var paramSetGlobalCmd = &cobra.Command{
 		}
 
 		globalOpt := action.ParamSetGlobal(true)
-		return action.ParamSet(fs, nsName, key, value, globalOpt)
+		envOpt := action.ParamSetWithEnv(viper.GetString(vParamSetGlobalEnv))
+		return action.ParamSet(fs, nsName, key, value, globalOpt, envOpt)
 	},
 }
 
 func init() {
 	paramCmd.AddCommand(paramSetGlobalCmd)
 
-	// TODO: support global in env
+	paramSetGlobalCmd.Flags().String(flagEnv, "", "Environment to set the global param in")
+	viper.BindPFlag(vParamSetGlobalEnv, paramSetGlobalCmd.Flags().Lookup(flagEnv))
 }
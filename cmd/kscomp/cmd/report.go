@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "report",
+	Long:  `report`,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+}
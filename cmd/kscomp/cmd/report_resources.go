@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vReportResourcesCompare = "report-resources-compare"
+	vReportResourcesOutput  = "report-resources-output"
+)
+
+// reportResourcesCmd represents the report resources command
+var reportResourcesCmd = &cobra.Command{
+	Use:   "resources <environment>",
+	Short: "report resource requests and limits",
+	Long: `Report the CPU, memory and ephemeral storage requested by the workloads in
+an environment. Container requests and limits are multiplied by replicas and
+summed per component, per component namespace and for the environment.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("report resources <environment>")
+		}
+
+		compare := viper.GetString(vReportResourcesCompare)
+		output := viper.GetString(vReportResourcesOutput)
+
+		return action.ReportResources(fs, args[0],
+			action.ReportResourcesWithCompare(compare),
+			action.ReportResourcesWithOutput(output))
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportResourcesCmd)
+
+	reportResourcesCmd.Flags().String(flagCompare, "", "Show the change from this environment")
+	viper.BindPFlag(vReportResourcesCompare, reportResourcesCmd.Flags().Lookup(flagCompare))
+
+	reportResourcesCmd.Flags().StringP(flagOutput, "o", "table", "Output format. Valid options: table, json")
+	viper.BindPFlag(vReportResourcesOutput, reportResourcesCmd.Flags().Lookup(flagOutput))
+}
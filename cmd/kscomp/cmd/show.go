@@ -16,6 +16,7 @@ package cmd
 
 import (
 	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -23,18 +24,39 @@ import (
 const (
 	vShowEnv       = "show-env"
 	vShowComponent = "show-component"
+	vShowOutput    = "show-output"
+	vShowOutputDir = "show-output-dir"
+	vShowKustomize = "show-kustomization"
 )
 
 // showCmd represents the show command
 var showCmd = &cobra.Command{
-	Use:   "show",
+	Use:   "show [environment]",
 	Short: "show a component",
 	Long:  `show a component`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		env := viper.GetString(vShowEnv)
+		switch len(args) {
+		case 0:
+		case 1:
+			env = args[0]
+		default:
+			return errors.New("show [environment]")
+		}
+
 		components := viper.GetStringSlice(vShowComponent)
+		output := viper.GetString(vShowOutput)
+		outputDir := viper.GetString(vShowOutputDir)
+		kustomization := viper.GetBool(vShowKustomize)
+
+		if kustomization && outputDir == "" {
+			return errors.Errorf("--%s requires --%s", flagKustomization, flagOutputDir)
+		}
 
-		return action.Show(fs, env, action.ShowWithComponents(components...))
+		return action.Show(fs, env,
+			action.ShowWithComponents(components...),
+			action.ShowWithFormat(output),
+			action.ShowWithOutputDir(outputDir, kustomization))
 	},
 }
 
@@ -46,4 +68,13 @@ func init() {
 
 	showCmd.Flags().StringSliceP(flagComponent, "c", nil, "Components to include")
 	viper.BindPFlag(vShowComponent, showCmd.Flags().Lookup(flagComponent))
+
+	showCmd.Flags().StringP(flagOutput, "o", "yaml", "Output format. Valid options: yaml, json, jsonl, name, jsonpath=<expr>, go-template=<tmpl>")
+	viper.BindPFlag(vShowOutput, showCmd.Flags().Lookup(flagOutput))
+
+	showCmd.Flags().String(flagOutputDir, "", "Write one file per object to this directory. Stale YAML files in the directory are removed; with --component, only those of the selected components")
+	viper.BindPFlag(vShowOutputDir, showCmd.Flags().Lookup(flagOutputDir))
+
+	showCmd.Flags().Bool(flagKustomization, false, "Generate a kustomization.yaml in the output directory")
+	viper.BindPFlag(vShowKustomize, showCmd.Flags().Lookup(flagKustomization))
 }
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vTestUpdate = "test-update"
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:   "test [environment...]",
+	Short: "compare rendered environments to golden files",
+	Long: `Render environments and compare the output to the golden files in
+tests/<environment>.yaml. All environments are tested if none are given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		update := viper.GetBool(vTestUpdate)
+		return action.Test(fs, args, action.TestWithUpdate(update))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+
+	testCmd.Flags().Bool(flagUpdate, false, "Rewrite the golden files with the rendered output")
+	viper.BindPFlag(vTestUpdate, testCmd.Flags().Lookup(flagUpdate))
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vTestUnitEnv = "test-unit-env"
+)
+
+// testUnitCmd represents the test-unit command
+var testUnitCmd = &cobra.Command{
+	Use:   "test-unit",
+	Short: "run jsonnet unit tests for components",
+	Long: `Run the jsonnet unit tests found in components/**/_test/*.jsonnet. Tests
+can import assertions from "kscomp/test.libsonnet".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env := viper.GetString(vTestUnitEnv)
+		return action.TestUnit(fs, env)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(testUnitCmd)
+
+	testUnitCmd.Flags().String(flagEnv, "default", "Environment which provides the ksonnet libraries")
+	viper.BindPFlag(vTestUnitEnv, testUnitCmd.Flags().Lookup(flagEnv))
+}
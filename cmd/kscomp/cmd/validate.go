@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/bryanl/woowoo/action"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <environment>",
+	Short: "validate params against component schemas",
+	Long: `Validate the effective params of each component in an environment against
+the component's params schema, which is declared in <component>.schema.json.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("validate <environment>")
+		}
+
+		return action.Validate(fs, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
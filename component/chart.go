@@ -0,0 +1,447 @@
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bryanl/woowoo/k8sutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	chartFile       = "Chart.yaml"
+	chartValuesFile = "values.yaml"
+	chartTemplates  = "templates"
+)
+
+// IsChartDir reports if a directory is an unpacked Helm chart.
+func IsChartDir(fs afero.Fs, dir string) (bool, error) {
+	return afero.Exists(fs, filepath.Join(dir, chartFile))
+}
+
+// Chart is a component based on a local, unpacked Helm chart. The chart is
+// rendered offline with the chart's default values overridden by the
+// component's params. Subcharts are not rendered.
+type Chart struct {
+	app        app.App
+	nsName     string
+	source     string
+	paramsPath string
+}
+
+var _ Component = (*Chart)(nil)
+
+// NewChart creates an instance of Chart. `source` is the chart directory.
+func NewChart(a app.App, nsName, source, paramsPath string) *Chart {
+	return &Chart{
+		app:        a,
+		nsName:     nsName,
+		source:     source,
+		paramsPath: paramsPath,
+	}
+}
+
+// Name is the name of this component. It is the name of the chart directory.
+func (c *Chart) Name(wantsNameSpaced bool) string {
+	name := filepath.Base(c.source)
+	if !wantsNameSpaced {
+		return name
+	}
+
+	return strings.TrimPrefix(path.Join(c.nsName, name), "/")
+}
+
+// Objects renders the chart's templates to a slice of apimachinery
+// unstructured objects.
+func (c *Chart) Objects(paramsStr, envName string) ([]*unstructured.Unstructured, error) {
+	if paramsStr == "" {
+		paramsData, err := readFile(c.app.Fs(), c.paramsPath)
+		if err != nil {
+			return nil, err
+		}
+
+		paramsStr, err = resolveParams(c.app, c.nsName, paramsData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := c.values(paramsStr)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := c.metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"Values": values,
+		"Chart":  metadata,
+		"Release": map[string]interface{}{
+			"Name":      c.Name(false),
+			"Namespace": "default",
+			"Service":   "kscomp",
+			"IsInstall": true,
+			"IsUpgrade": false,
+			"Revision":  1,
+		},
+		"Environment": envName,
+	}
+
+	manifests, err := c.render(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "render chart %s", c.Name(true))
+	}
+
+	var objects []runtime.Object
+	for _, manifest := range manifests {
+		decoded, err := decodeYAMLStream(manifest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decode rendered chart %s", c.Name(true))
+		}
+
+		objects = append(objects, decoded...)
+	}
+
+	return k8sutil.FlattenToV1(objects)
+}
+
+// DefaultValues returns the chart's default values from values.yaml.
+func (c *Chart) DefaultValues() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	valuesPath := filepath.Join(c.source, chartValuesFile)
+	exists, err := afero.Exists(c.app.Fs(), valuesPath)
+	if err != nil || !exists {
+		return values, err
+	}
+
+	b, err := afero.ReadFile(c.app.Fs(), valuesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(b, &values); err != nil {
+		return nil, errors.Wrap(err, "decode values.yaml")
+	}
+
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	return values, nil
+}
+
+// values are the chart's default values overridden by the component params.
+func (c *Chart) values(paramsStr string) (map[string]interface{}, error) {
+	values, err := c.DefaultValues()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc patchDoc
+	if err := json.Unmarshal([]byte(paramsStr), &doc); err != nil {
+		return nil, errors.Wrap(err, "decode params")
+	}
+
+	if overrides, ok := doc.Components[c.Name(false)].(map[string]interface{}); ok {
+		mergeValues(values, overrides)
+	}
+
+	return values, nil
+}
+
+// mergeValues merges src into dst. Maps are merged, and all other values
+// in src replace the values in dst.
+func mergeValues(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, isSrcMap := v.(map[string]interface{})
+		dstMap, isDstMap := dst[k].(map[string]interface{})
+		if isSrcMap && isDstMap {
+			mergeValues(dstMap, srcMap)
+			continue
+		}
+
+		dst[k] = v
+	}
+}
+
+// metadata returns Chart.yaml with keys capitalized as templates expect,
+// e.g. `.Chart.Name`.
+func (c *Chart) metadata() (map[string]interface{}, error) {
+	b, err := afero.ReadFile(c.app.Fs(), filepath.Join(c.source, chartFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, errors.Wrap(err, "decode Chart.yaml")
+	}
+
+	metadata := make(map[string]interface{})
+	for k, v := range raw {
+		if k == "" {
+			continue
+		}
+
+		metadata[strings.ToUpper(k[:1])+k[1:]] = v
+	}
+
+	return metadata, nil
+}
+
+// render renders the chart's templates. Files in templates which start with
+// `_` only contain definitions and are not rendered.
+func (c *Chart) render(data map[string]interface{}) ([][]byte, error) {
+	dir := filepath.Join(c.source, chartTemplates)
+
+	fis, err := afero.ReadDir(c.app.Fs(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root := template.New(c.Name(false)).Option("missingkey=zero")
+
+	funcs := templateFuncs()
+	funcs["include"] = func(name string, data interface{}) (string, error) {
+		var buf bytes.Buffer
+		if err := root.ExecuteTemplate(&buf, name, data); err != nil {
+			return "", err
+		}
+
+		return buf.String(), nil
+	}
+	funcs["tpl"] = func(text string, data interface{}) (string, error) {
+		t, err := root.Clone()
+		if err != nil {
+			return "", err
+		}
+
+		if t, err = t.New("tpl").Parse(text); err != nil {
+			return "", err
+		}
+
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", err
+		}
+
+		return buf.String(), nil
+	}
+	root.Funcs(funcs)
+
+	var names []string
+	for _, fi := range fis {
+		if fi.IsDir() {
+			continue
+		}
+
+		b, err := afero.ReadFile(c.app.Fs(), filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Join(c.Name(false), chartTemplates, fi.Name())
+		if _, err := root.New(name).Parse(string(b)); err != nil {
+			return nil, errors.Wrapf(err, "parse %s", name)
+		}
+
+		if isChartManifest(fi.Name()) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var manifests [][]byte
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := root.ExecuteTemplate(&buf, name, data); err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, buf.Bytes())
+	}
+
+	return manifests, nil
+}
+
+func isChartManifest(name string) bool {
+	if strings.HasPrefix(name, "_") {
+		return false
+	}
+
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// SeedParams sets the component's params from the chart's default values.
+// Values which can't be expressed as params, like arrays of objects, are
+// left out and continue to come from values.yaml.
+func (c *Chart) SeedParams() error {
+	values, err := c.DefaultValues()
+	if err != nil {
+		return err
+	}
+
+	paramsData, err := readFile(c.app.Fs(), c.paramsPath)
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Update(
+		[]string{paramsComponentRoot, c.Name(false)}, paramsData, seedableValues(values))
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.app.Fs(), c.paramsPath, []byte(updatedParams), 0644)
+}
+
+// seedableValues returns the values which can be stored as params.
+func seedableValues(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+
+	for k, v := range m {
+		switch t := v.(type) {
+		case string, bool, float64:
+			out[k] = t
+		case map[string]interface{}:
+			out[k] = seedableValues(t)
+		case []interface{}:
+			if isScalarArray(t) {
+				out[k] = t
+			}
+		}
+	}
+
+	return out
+}
+
+func isScalarArray(a []interface{}) bool {
+	for _, item := range a {
+		switch item.(type) {
+		case string, bool, float64:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetParam set parameter for a component.
+func (c *Chart) SetParam(path []string, value interface{}, options ParamOptions) error {
+	paramsData, err := readFile(c.app.Fs(), c.paramsPath)
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Set(path, paramsData, c.Name(false), value, paramsComponentRoot, params.SetWithType(options.Type))
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.app.Fs(), c.paramsPath, []byte(updatedParams), 0644)
+}
+
+// DeleteParam deletes a param.
+func (c *Chart) DeleteParam(path []string, options ParamOptions) error {
+	paramsData, err := readFile(c.app.Fs(), c.paramsPath)
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Delete(path, paramsData, c.Name(false), paramsComponentRoot)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.app.Fs(), c.paramsPath, []byte(updatedParams), 0644)
+}
+
+// Params returns params for a component.
+func (c *Chart) Params() ([]NamespaceParameter, error) {
+	paramsData, err := readFile(c.app.Fs(), c.paramsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := params.ToMap(c.Name(false), paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find components")
+	}
+
+	var params []NamespaceParameter
+	for k, v := range props {
+		vStr, err := ParamValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, NamespaceParameter{
+			Component: c.Name(false),
+			Key:       k,
+			Index:     "0",
+			Value:     vStr,
+		})
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Key < params[j].Key
+	})
+
+	return params, nil
+}
+
+// Summarize creates a summary for the component. The chart is rendered with
+// the namespace's params to find the objects it creates.
+func (c *Chart) Summarize() ([]Summary, error) {
+	objects, err := c.Objects("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for i, obj := range objects {
+		summaries = append(summaries, Summary{
+			ComponentName: c.Name(false),
+			IndexStr:      strconv.Itoa(i),
+			Index:         i,
+			Type:          "helm",
+			APIVersion:    obj.GetAPIVersion(),
+			Kind:          obj.GetKind(),
+			Name:          obj.GetName(),
+		})
+	}
+
+	return summaries, nil
+}
+
+func readFile(fs afero.Fs, path string) (string, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,141 @@
+package component
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func withChart(t *testing.T, fn func(*Chart, afero.Fs)) {
+	app, fs := appMock("/")
+
+	files := []string{
+		"redis/Chart.yaml",
+		"redis/values.yaml",
+		"redis/templates/_helpers.tpl",
+		"redis/templates/deployment.yaml",
+		"redis/templates/service.yaml",
+		"redis/templates/NOTES.txt",
+		"params.libsonnet",
+	}
+	for _, file := range files {
+		stageFile(t, fs, filepath.Join("chart", file), filepath.Join("/components/ns1", file))
+	}
+
+	c := NewChart(app, "ns1", "/components/ns1/redis", "/components/ns1/params.libsonnet")
+
+	fn(c, fs)
+}
+
+func TestChart_Name(t *testing.T) {
+	withChart(t, func(c *Chart, fs afero.Fs) {
+		require.Equal(t, "redis", c.Name(false))
+		require.Equal(t, "ns1/redis", c.Name(true))
+	})
+}
+
+func TestChart_Objects(t *testing.T) {
+	withChart(t, func(c *Chart, fs afero.Fs) {
+		list, err := c.Objects(`{"components": {}}`, "default")
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+
+		deployment := list[0]
+		require.Equal(t, "Deployment", deployment.GetKind())
+		require.Equal(t, "redis-redis", deployment.GetName())
+		require.Equal(t, map[string]string{"chart": "redis-1.2.0"}, deployment.GetLabels())
+
+		spec := deployment.Object["spec"].(map[string]interface{})
+		require.Equal(t, int64(1), spec["replicas"])
+
+		podSpec := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})
+		container := podSpec["containers"].([]interface{})[0].(map[string]interface{})
+		require.Equal(t, "redis:4.0.9", container["image"])
+		require.Equal(t, []interface{}{"--appendonly", "yes"}, container["args"])
+		require.Len(t, podSpec["tolerations"], 1)
+
+		service := list[1]
+		require.Equal(t, "Service", service.GetKind())
+		require.Equal(t, "redis-redis", service.GetName())
+	})
+}
+
+func TestChart_Objects_overrides(t *testing.T) {
+	withChart(t, func(c *Chart, fs afero.Fs) {
+		paramsStr := `{"components": {"redis": {"replicas": 3, "image": {"tag": "4.0.10"}}}}`
+
+		list, err := c.Objects(paramsStr, "default")
+		require.NoError(t, err)
+
+		spec := list[0].Object["spec"].(map[string]interface{})
+		require.Equal(t, int64(3), spec["replicas"])
+
+		podSpec := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})
+		container := podSpec["containers"].([]interface{})[0].(map[string]interface{})
+		require.Equal(t, "redis:4.0.10", container["image"])
+	})
+}
+
+func TestChart_SeedParams(t *testing.T) {
+	withChart(t, func(c *Chart, fs afero.Fs) {
+		require.NoError(t, c.SeedParams())
+
+		b, err := afero.ReadFile(fs, "/components/ns1/params.libsonnet")
+		require.NoError(t, err)
+
+		m, err := params.ToMap("redis", string(b), paramsComponentRoot)
+		require.NoError(t, err)
+
+		expected := map[string]interface{}{
+			"image": map[string]interface{}{
+				"repository": "redis",
+				"tag":        "4.0.9",
+			},
+			"replicas":  float64(1),
+			"port":      float64(6379),
+			"args":      []interface{}{"--appendonly", "yes"},
+			"resources": map[string]interface{}{},
+		}
+		require.Equal(t, expected, m)
+
+		require.NoError(t, c.SetParam([]string{"replicas"}, 2, ParamOptions{}))
+
+		list, err := c.Objects("", "default")
+		require.NoError(t, err)
+
+		spec := list[0].Object["spec"].(map[string]interface{})
+		require.Equal(t, int64(2), spec["replicas"])
+	})
+}
+
+func TestChart_Summarize(t *testing.T) {
+	withChart(t, func(c *Chart, fs afero.Fs) {
+		got, err := c.Summarize()
+		require.NoError(t, err)
+
+		expected := []Summary{
+			{ComponentName: "redis", IndexStr: "0", Index: 0, Type: "helm", APIVersion: "apps/v1beta1", Kind: "Deployment", Name: "redis-redis"},
+			{ComponentName: "redis", IndexStr: "1", Index: 1, Type: "helm", APIVersion: "v1", Kind: "Service", Name: "redis-redis"},
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestNamespace_Components_chart(t *testing.T) {
+	withChart(t, func(c *Chart, fs afero.Fs) {
+		ns := NewNamespace(c.app, "ns1")
+
+		components, err := ns.Components()
+		require.NoError(t, err)
+		require.Len(t, components, 1)
+		require.Equal(t, "ns1/redis", components[0].Name(true))
+
+		namespaces, err := Namespaces(c.app)
+		require.NoError(t, err)
+		require.Len(t, namespaces, 1)
+	})
+}
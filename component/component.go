@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/bryanl/woowoo/params"
 	"github.com/ksonnet/ksonnet/metadata/app"
 
 	"github.com/pkg/errors"
@@ -14,6 +15,9 @@ import (
 // ParamOptions is options for parameters.
 type ParamOptions struct {
 	Index int
+	// Type is the explicit type of a value which is set. A value with an
+	// explicit type can change the type of the current value.
+	Type params.ValueType
 }
 
 // Summary summarizes items found in components.
@@ -53,8 +57,14 @@ const (
 	componentsRoot = "components"
 	// paramsFile is the params file for a component namespace.
 	paramsFile = "params.libsonnet"
+	// envRoot is the name of the directory which houses environments.
+	envRoot = "environments"
 )
 
+// TestDir is the name of the directories which contain component tests.
+// Test directories are never component namespaces.
+const TestDir = "_test"
+
 // Path returns returns the file system path for a component.
 func Path(a app.App, name string) (string, error) {
 	ns, localName := ExtractNamespacedComponent(a, name)
@@ -72,7 +82,10 @@ func Path(a app.App, name string) (string, error) {
 			continue
 		}
 
-		base := strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))
+		base := strings.TrimSuffix(fi.Name(), templateExt)
+		base = strings.TrimSuffix(base, patchExt)
+		base = strings.TrimSuffix(base, generatorExt)
+		base = strings.TrimSuffix(base, filepath.Ext(base))
 		if _, ok := files[base]; ok {
 			return "", errors.Errorf("Found multiple component files with component name %q", name)
 		}
@@ -0,0 +1,60 @@
+package component
+
+import (
+	"path/filepath"
+
+	utilyaml "github.com/bryanl/woowoo/pkg/util/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/spf13/afero"
+)
+
+// EnvParamsPath is the path to an environment's params.libsonnet.
+func EnvParamsPath(a app.App, envName string) string {
+	return filepath.Join(a.Root(), envRoot, envName, paramsFile)
+}
+
+// ReadEnvParams reads the params for an environment.
+func ReadEnvParams(a app.App, envName string) (string, error) {
+	b, err := afero.ReadFile(a.Fs(), EnvParamsPath(a, envName))
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// WriteEnvParams writes the params for an environment.
+func WriteEnvParams(a app.App, envName, src string) error {
+	return afero.WriteFile(a.Fs(), EnvParamsPath(a, envName), []byte(src), 0644)
+}
+
+// ParamsEntry is the name of a component's entry in params. YAML components
+// have an entry for each object in their manifest.
+func ParamsEntry(c Component, index int) string {
+	if y, ok := c.(*YAML); ok {
+		return y.entry(index)
+	}
+
+	return c.Name(false)
+}
+
+// ParamsEntries returns a component's entries in params and the index of the
+// object each entry belongs to.
+func ParamsEntries(c Component) (map[string]int, error) {
+	y, ok := c.(*YAML)
+	if !ok {
+		return map[string]int{c.Name(false): 0}, nil
+	}
+
+	readers, err := utilyaml.Decode(y.app.Fs(), y.source)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]int)
+	for i := range readers {
+		entries[y.entry(i)] = i
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,503 @@
+package component
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// generatorExt is the extension for generator components.
+	generatorExt = ".generator.yaml"
+)
+
+// Renamer is a component which renames the objects it renders, e.g. by adding
+// a hash of their contents to their names. Objects are renamed after patches
+// are applied. References to the objects in the other components of its
+// namespace are renamed to match.
+type Renamer interface {
+	Component
+
+	// Rename renames objects the component rendered. It returns the objects'
+	// names and the names they are renamed to.
+	Rename(objects []*unstructured.Unstructured) ([]Rename, error)
+}
+
+// Rename is an object's name and the name it is renamed to.
+type Rename struct {
+	Kind string
+	From string
+	To   string
+}
+
+// GeneratorFile is the contents of a generator component. It generates a
+// ConfigMap or a Secret. Paths are relative to the generator's directory, and
+// must be in the app. The secret key file can't be read.
+//
+//	kind: ConfigMap
+//	name: nginx
+//	hashSuffix: true
+//	files:
+//	- config/nginx.conf
+//	- default.conf=config/site.conf
+//	dirs:
+//	- config/snippets
+//	envFiles:
+//	- config/nginx.env
+//	literals:
+//	- LOG_LEVEL=debug
+type GeneratorFile struct {
+	// Kind is ConfigMap or Secret.
+	Kind string `json:"kind"`
+	// Name is the name of the object. It defaults to the component name.
+	Name string `json:"name,omitempty"`
+	// Type is the type of a Secret. It defaults to Opaque.
+	Type string `json:"type,omitempty"`
+	// HashSuffix adds a hash of the contents to the name, so objects which
+	// refer to it are updated when it changes.
+	HashSuffix bool `json:"hashSuffix,omitempty"`
+	// Files are files to add. The key is the file name unless the file is
+	// given as `key=path`.
+	Files []string `json:"files,omitempty"`
+	// Dirs are directories whose files are added.
+	Dirs []string `json:"dirs,omitempty"`
+	// EnvFiles are files of `KEY=value` lines to add.
+	EnvFiles []string `json:"envFiles,omitempty"`
+	// Literals are `KEY=value` pairs to add.
+	Literals []string `json:"literals,omitempty"`
+}
+
+var reDataKey = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// Generator is a component which generates a ConfigMap or a Secret from
+// files and literals.
+type Generator struct {
+	app    app.App
+	nsName string
+	source string
+}
+
+var _ Renamer = (*Generator)(nil)
+
+// NewGenerator creates an instance of Generator.
+func NewGenerator(a app.App, nsName, source string) *Generator {
+	return &Generator{
+		app:    a,
+		nsName: nsName,
+		source: source,
+	}
+}
+
+// Name is the name of this component.
+func (g *Generator) Name(wantsNameSpaced bool) string {
+	name := strings.TrimSuffix(filepath.Base(g.source), generatorExt)
+	if !wantsNameSpaced {
+		return name
+	}
+
+	return strings.TrimPrefix(path.Join(g.nsName, name), "/")
+}
+
+// Objects generates the ConfigMap or Secret. The hash suffix is added to the
+// name by Rename.
+func (g *Generator) Objects(paramsStr, envName string) ([]*unstructured.Unstructured, error) {
+	gf, err := g.read()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.data(gf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "generate %s", g.Name(true))
+	}
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       gf.Kind,
+			"metadata": map[string]interface{}{
+				"name": gf.Name,
+			},
+		},
+	}
+
+	switch gf.Kind {
+	case "ConfigMap":
+		setConfigMapData(obj.Object, data)
+	case "Secret":
+		obj.Object["type"] = gf.Type
+		setSecretData(obj.Object, data)
+	}
+
+	return []*unstructured.Unstructured{obj}, nil
+}
+
+// Rename adds a hash of the objects' contents to their names if the generator
+// has a hash suffix.
+func (g *Generator) Rename(objects []*unstructured.Unstructured) ([]Rename, error) {
+	gf, err := g.read()
+	if err != nil {
+		return nil, err
+	}
+
+	if !gf.HashSuffix {
+		return nil, nil
+	}
+
+	var renames []Rename
+	for _, obj := range objects {
+		hash, err := contentHash(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		r := Rename{Kind: obj.GetKind(), From: obj.GetName(), To: obj.GetName() + "-" + hash}
+		obj.SetName(r.To)
+		renames = append(renames, r)
+	}
+
+	return renames, nil
+}
+
+func (g *Generator) read() (*GeneratorFile, error) {
+	b, err := afero.ReadFile(g.app.Fs(), g.source)
+	if err != nil {
+		return nil, err
+	}
+
+	var gf GeneratorFile
+	if err := yaml.Unmarshal(b, &gf); err != nil {
+		return nil, errors.Wrapf(err, "decode generator %s", g.Name(true))
+	}
+
+	switch gf.Kind {
+	case "ConfigMap":
+		if gf.Type != "" {
+			return nil, errors.Errorf("generator %s sets a type, but only Secrets have types", g.Name(true))
+		}
+	case "Secret":
+		if gf.Type == "" {
+			gf.Type = "Opaque"
+		}
+	default:
+		return nil, errors.Errorf("generator %s has kind %q; it must be ConfigMap or Secret", g.Name(true), gf.Kind)
+	}
+
+	if gf.Name == "" {
+		gf.Name = g.Name(false)
+	}
+
+	return &gf, nil
+}
+
+// data reads the generator's sources. Keys must be unique.
+func (g *Generator) data(gf *GeneratorFile) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	add := func(key string, value []byte, source string) error {
+		if !reDataKey.MatchString(key) {
+			return errors.Errorf("%s: %q is not a valid key", source, key)
+		}
+
+		if _, ok := data[key]; ok {
+			return errors.Errorf("%s: key %q is already set", source, key)
+		}
+
+		data[key] = value
+		return nil
+	}
+
+	// sources are read relative to the app root, and can't be read from
+	// outside of it
+	fs := afero.NewBasePathFs(g.app.Fs(), g.app.Root())
+
+	for _, file := range gf.Files {
+		key, p := filepath.Base(file), file
+		if parts := strings.SplitN(file, "=", 2); len(parts) == 2 {
+			key, p = parts[0], parts[1]
+		}
+
+		b, err := g.readFile(fs, p)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := add(key, b, file); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range gf.Dirs {
+		dir, err := g.appPath(d)
+		if err != nil {
+			return nil, err
+		}
+
+		fis, err := afero.ReadDir(fs, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fi := range fis {
+			if fi.IsDir() {
+				continue
+			}
+
+			b, err := g.readFile(fs, filepath.Join(d, fi.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			if err := add(fi.Name(), b, d); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, envFile := range gf.EnvFiles {
+		b, err := g.readFile(fs, envFile)
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		for n := 1; scanner.Scan(); n++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			source := envFile + ":" + strconv.Itoa(n)
+			key, value, err := splitPair(line)
+			if err != nil {
+				return nil, errors.Wrap(err, source)
+			}
+
+			if err := add(key, []byte(value), source); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, literal := range gf.Literals {
+		key, value, err := splitPair(literal)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := add(key, []byte(value), literal); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// readFile reads a source from fs, which is rooted at the app root. Paths are
+// relative to the generator.
+func (g *Generator) readFile(fs afero.Fs, p string) ([]byte, error) {
+	rel, err := g.appPath(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.IsSecretKeyFile(g.app.Root(), rel) {
+		return nil, errors.Errorf("%s is the secret key file", p)
+	}
+
+	return afero.ReadFile(fs, rel)
+}
+
+// appPath converts a path relative to the generator to a path relative to the
+// app root. It returns an error if the path is outside of the app.
+func (g *Generator) appPath(p string) (string, error) {
+	rel, err := filepath.Rel(g.app.Root(), filepath.Join(filepath.Dir(g.source), p))
+	if err != nil {
+		return "", err
+	}
+
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", errors.Errorf("%s is outside of the app", p)
+	}
+
+	return rel, nil
+}
+
+func splitPair(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return "", "", errors.Errorf("%q is not KEY=value", s)
+	}
+
+	return strings.TrimSpace(parts[0]), parts[1], nil
+}
+
+// setConfigMapData sets a ConfigMap's data. Values which aren't UTF-8 are
+// binary data.
+func setConfigMapData(obj map[string]interface{}, data map[string][]byte) {
+	text := make(map[string]interface{})
+	binary := make(map[string]interface{})
+	for k, v := range data {
+		if utf8.Valid(v) {
+			text[k] = string(v)
+			continue
+		}
+
+		binary[k] = base64.StdEncoding.EncodeToString(v)
+	}
+
+	if len(text) > 0 {
+		obj["data"] = text
+	}
+
+	if len(binary) > 0 {
+		obj["binaryData"] = binary
+	}
+}
+
+func setSecretData(obj map[string]interface{}, data map[string][]byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	encoded := make(map[string]interface{})
+	for k, v := range data {
+		encoded[k] = base64.StdEncoding.EncodeToString(v)
+	}
+
+	obj["data"] = encoded
+}
+
+// contentHash hashes an object's contents. The name isn't part of the hash.
+func contentHash(obj map[string]interface{}) (string, error) {
+	contents := make(map[string]interface{})
+	for k, v := range obj {
+		if k != "metadata" {
+			contents[k] = v
+		}
+	}
+
+	b, err := json.Marshal(contents)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:10], nil
+}
+
+// nameReferences are the fields which refer to ConfigMaps and Secrets by
+// name, keyed by kind, and the fields in them which hold the name.
+var nameReferences = map[string]map[string][]string{
+	"ConfigMap": {
+		"configMap":       {"name"},
+		"configMapRef":    {"name"},
+		"configMapKeyRef": {"name"},
+	},
+	"Secret": {
+		"secret":           {"secretName", "name"},
+		"secretRef":        {"name"},
+		"secretKeyRef":     {"name"},
+		"imagePullSecrets": {"name"},
+	},
+}
+
+// RenameReferences renames an object's references to a renamed object, e.g.
+// the ConfigMaps in a Deployment's volumes.
+func RenameReferences(obj *unstructured.Unstructured, r Rename) {
+	renameReferences(obj.Object, nameReferences[r.Kind], r)
+}
+
+func renameReferences(v interface{}, fields map[string][]string, r Rename) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if names, ok := fields[k]; ok {
+				renameIn(child, names, r)
+			}
+
+			renameReferences(child, fields, r)
+		}
+	case []interface{}:
+		for _, child := range t {
+			renameReferences(child, fields, r)
+		}
+	}
+}
+
+// renameIn renames the name in a reference or a list of references.
+func renameIn(ref interface{}, names []string, r Rename) {
+	switch t := ref.(type) {
+	case map[string]interface{}:
+		for _, name := range names {
+			if t[name] == r.From {
+				t[name] = r.To
+			}
+		}
+	case []interface{}:
+		for _, child := range t {
+			renameIn(child, names, r)
+		}
+	}
+}
+
+// SetParam returns an error since generators do not have params.
+func (g *Generator) SetParam(path []string, value interface{}, options ParamOptions) error {
+	return errors.Errorf("generator component %s does not have params", g.Name(true))
+}
+
+// DeleteParam returns an error since generators do not have params.
+func (g *Generator) DeleteParam(path []string, options ParamOptions) error {
+	return errors.Errorf("generator component %s does not have params", g.Name(true))
+}
+
+// Params returns no params since generators do not have params.
+func (g *Generator) Params() ([]NamespaceParameter, error) {
+	return nil, nil
+}
+
+// Summarize creates a summary for the generated object.
+func (g *Generator) Summarize() ([]Summary, error) {
+	objects, err := g.Objects("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := g.Rename(objects); err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for i, obj := range objects {
+		summaries = append(summaries, Summary{
+			ComponentName: g.Name(false),
+			IndexStr:      strconv.Itoa(i),
+			Index:         i,
+			Type:          "generator",
+			APIVersion:    obj.GetAPIVersion(),
+			Kind:          obj.GetKind(),
+			Name:          obj.GetName(),
+		})
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,215 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func withGenerator(t *testing.T, name string, fn func(*Generator, afero.Fs)) {
+	app, fs := appMock("/")
+
+	files := []string{
+		"config/nginx.conf",
+		"config/site.conf",
+		"config/nginx.env",
+		"config/snippets/gzip.conf",
+		name + ".generator.yaml",
+	}
+
+	for _, file := range files {
+		stageFile(t, fs, "generator/"+file, "/components/"+file)
+	}
+
+	g := NewGenerator(app, "/", "/components/"+name+".generator.yaml")
+
+	fn(g, fs)
+}
+
+func TestGenerator_Name(t *testing.T) {
+	withGenerator(t, "nginx", func(g *Generator, fs afero.Fs) {
+		require.Equal(t, "nginx", g.Name(false))
+		require.Equal(t, "nginx", g.Name(true))
+	})
+}
+
+func TestGenerator_Objects_configMap(t *testing.T) {
+	withGenerator(t, "nginx", func(g *Generator, fs afero.Fs) {
+		got, err := g.Objects("", "default")
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+
+		expected := map[string]interface{}{
+			"nginx.conf":   "worker_processes 1;\n",
+			"default.conf": "server {\n  listen 80;\n}\n",
+			"gzip.conf":    "gzip on;\n",
+			"WORKERS":      "1",
+			"TIMEOUT":      "30s",
+			"LOG_LEVEL":    "debug",
+		}
+
+		require.Equal(t, "ConfigMap", got[0].GetKind())
+		require.Equal(t, expected, got[0].Object["data"])
+		require.Equal(t, "nginx", got[0].GetName())
+	})
+}
+
+func TestGenerator_Objects_secret(t *testing.T) {
+	withGenerator(t, "db", func(g *Generator, fs afero.Fs) {
+		got, err := g.Objects("", "default")
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+
+		expected := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"type":       "Opaque",
+				"metadata": map[string]interface{}{
+					"name": "db-credentials",
+				},
+				"data": map[string]interface{}{
+					"username": "YWRtaW4=",
+					"password": "aHVudGVyMg==",
+				},
+			},
+		}
+
+		require.Equal(t, expected, got[0])
+	})
+}
+
+func TestGenerator_Objects_invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+	}{
+		{name: "kind", src: "kind: Deployment\n"},
+		{name: "config map type", src: "kind: ConfigMap\ntype: Opaque\n"},
+		{name: "duplicate key", src: "kind: ConfigMap\nliterals:\n- a=1\n- a=2\n"},
+		{name: "invalid key", src: "kind: ConfigMap\nliterals:\n- a b=1\n"},
+		{name: "literal", src: "kind: ConfigMap\nliterals:\n- a\n"},
+		{name: "missing file", src: "kind: ConfigMap\nfiles:\n- missing.conf\n"},
+		{name: "secret key file", src: "kind: Secret\nfiles:\n- ../.secret.key\n"},
+		{name: "secret key file in dir", src: "kind: Secret\ndirs:\n- ..\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withGenerator(t, "db", func(g *Generator, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/components/db.generator.yaml", []byte(tc.src), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/.secret.key", []byte("c2VjcmV0"), 0600))
+
+				_, err := g.Objects("", "default")
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestGenerator_Objects_outside_of_the_app(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+	}{
+		{name: "file", src: "kind: ConfigMap\nfiles:\n- ../../etc/passwd\n"},
+		{name: "dir", src: "kind: ConfigMap\ndirs:\n- ../../etc\n"},
+		{name: "env file", src: "kind: ConfigMap\nenvFiles:\n- ../../etc/passwd\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app, fs := appMock("/app")
+			require.NoError(t, afero.WriteFile(fs, "/etc/passwd", []byte("root=x\n"), 0644))
+			require.NoError(t, afero.WriteFile(fs, "/app/components/etc.generator.yaml", []byte(tc.src), 0644))
+
+			g := NewGenerator(app, "/", "/app/components/etc.generator.yaml")
+
+			_, err := g.Objects("", "default")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestGenerator_Rename(t *testing.T) {
+	withGenerator(t, "nginx", func(g *Generator, fs afero.Fs) {
+		objects, err := g.Objects("", "default")
+		require.NoError(t, err)
+
+		got, err := g.Rename(objects)
+		require.NoError(t, err)
+
+		require.Regexp(t, `^nginx-[0-9a-f]{10}$`, objects[0].GetName())
+
+		expected := []Rename{{Kind: "ConfigMap", From: "nginx", To: objects[0].GetName()}}
+		require.Equal(t, expected, got)
+
+		require.NoError(t, afero.WriteFile(fs, "/components/config/nginx.conf", []byte("worker_processes 2;\n"), 0644))
+
+		changed, err := g.Objects("", "default")
+		require.NoError(t, err)
+
+		_, err = g.Rename(changed)
+		require.NoError(t, err)
+		require.NotEqual(t, objects[0].GetName(), changed[0].GetName())
+	})
+
+	withGenerator(t, "db", func(g *Generator, fs afero.Fs) {
+		objects, err := g.Objects("", "default")
+		require.NoError(t, err)
+
+		got, err := g.Rename(objects)
+		require.NoError(t, err)
+		require.Empty(t, got)
+		require.Equal(t, "db-credentials", objects[0].GetName())
+	})
+}
+
+func TestGenerator_SetParam(t *testing.T) {
+	withGenerator(t, "nginx", func(g *Generator, fs afero.Fs) {
+		err := g.SetParam([]string{"replicas"}, 3, ParamOptions{})
+		require.Error(t, err)
+	})
+}
+
+func TestRenameReferences(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":     "Deployment",
+			"metadata": map[string]interface{}{"name": "nginx"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name": "nginx",
+								"envFrom": []interface{}{
+									map[string]interface{}{"configMapRef": map[string]interface{}{"name": "nginx"}},
+									map[string]interface{}{"secretRef": map[string]interface{}{"name": "nginx"}},
+								},
+							},
+						},
+						"volumes": []interface{}{
+							map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "nginx"}},
+							map[string]interface{}{"name": "other", "configMap": map[string]interface{}{"name": "other"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	RenameReferences(obj, Rename{Kind: "ConfigMap", From: "nginx", To: "nginx-abc"})
+
+	spec := obj.Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	envFrom := spec["containers"].([]interface{})[0].(map[string]interface{})["envFrom"].([]interface{})
+	volumes := spec["volumes"].([]interface{})
+
+	require.Equal(t, "nginx-abc", envFrom[0].(map[string]interface{})["configMapRef"].(map[string]interface{})["name"])
+	require.Equal(t, "nginx", envFrom[1].(map[string]interface{})["secretRef"].(map[string]interface{})["name"])
+	require.Equal(t, "nginx-abc", volumes[0].(map[string]interface{})["configMap"].(map[string]interface{})["name"])
+	require.Equal(t, "other", volumes[1].(map[string]interface{})["configMap"].(map[string]interface{})["name"])
+	require.Equal(t, "nginx", obj.GetName())
+}
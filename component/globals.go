@@ -0,0 +1,258 @@
+package component
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// GlobalParams are the global params which apply to the components in a
+// namespace. They cascade from the root namespace through each parent
+// namespace, and the closest namespace wins.
+type GlobalParams struct {
+	Values map[string]interface{}
+	// Sources maps each key in Values to the namespace which set it.
+	Sources map[string]string
+}
+
+// Globals returns the global params for a namespace.
+func (n *Namespace) Globals() (*GlobalParams, error) {
+	gp := &GlobalParams{
+		Values:  make(map[string]interface{}),
+		Sources: make(map[string]string),
+	}
+
+	for _, ns := range n.lineage() {
+		global, err := ns.ownGlobals()
+		if err != nil {
+			return nil, err
+		}
+
+		gp.Merge(ns.Name(), global)
+	}
+
+	return gp, nil
+}
+
+// lineage returns a namespace's ancestors, starting with the root namespace
+// and ending with the namespace itself.
+func (n *Namespace) lineage() []Namespace {
+	lineage := []Namespace{{envName: n.envName, app: n.app}}
+
+	path := strings.Trim(n.path, "/")
+	if path == "" {
+		return lineage
+	}
+
+	parts := strings.Split(path, "/")
+	for i := range parts {
+		ns := Namespace{
+			path:    strings.Join(parts[:i+1], "/"),
+			envName: n.envName,
+			app:     n.app,
+		}
+		lineage = append(lineage, ns)
+	}
+
+	return lineage
+}
+
+// Merge merges global params set by source over the current values, e.g. an
+// environment's globals.
+func (gp *GlobalParams) Merge(source string, values map[string]interface{}) {
+	mergeGlobal(gp.Values, values)
+	for k, v := range values {
+		if v == nil {
+			delete(gp.Sources, k)
+			continue
+		}
+		gp.Sources[k] = source
+	}
+}
+
+// ownGlobals returns the global block of a namespace's params.
+func (n *Namespace) ownGlobals() (map[string]interface{}, error) {
+	exists, err := afero.Exists(n.app.Fs(), n.ParamsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	s, err := n.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.ExtCode("params", s)
+
+	out, err := vm.EvaluateSnippet("snippet", snippetGlobal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluate globals for %s", nsErrorMsg("%s", strings.Trim(n.path, "/")))
+	}
+
+	var global map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &global); err != nil {
+		return nil, err
+	}
+
+	return global, nil
+}
+
+var snippetGlobal = `
+local params = std.extVar("params");
+
+if std.objectHas(params, "global") then params.global else {}
+`
+
+// mergeGlobal merges src into dst with JSON merge patch semantics.
+func mergeGlobal(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+
+		srcMap, ok := v.(map[string]interface{})
+		if !ok {
+			dst[k] = v
+			continue
+		}
+
+		dstMap, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dstMap = make(map[string]interface{})
+		} else {
+			dstMap = copyMap(dstMap)
+		}
+
+		mergeGlobal(dstMap, srcMap)
+		dst[k] = dstMap
+	}
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, v := range m {
+		if child, ok := v.(map[string]interface{}); ok {
+			v = copyMap(child)
+		}
+		out[k] = v
+	}
+
+	return out
+}
+
+// resolveParams applies the global params of a namespace to the components
+// in paramsData.
+func resolveParams(a app.App, nsName, paramsData string) (string, error) {
+	ns := NewNamespace(a, nsName)
+	globals, err := ns.Globals()
+	if err != nil {
+		return "", err
+	}
+
+	return globals.applyTo(paramsData)
+}
+
+// applyTo applies the global params to each component in params. It returns
+// a JSON encoded string of component parameters.
+func (gp *GlobalParams) applyTo(params string) (string, error) {
+	b, err := json.Marshal(gp.Values)
+	if err != nil {
+		return "", err
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.ExtCode("params", params)
+	vm.ExtCode("globals", string(b))
+
+	return vm.EvaluateSnippet("snippet", snippetMapGlobals)
+}
+
+var snippetMapGlobals = `
+local params = std.extVar("params");
+local globals = std.extVar("globals");
+local applyGlobal = function(key, value) std.mergePatch(value, globals);
+
+{
+	components: std.mapWithKey(applyGlobal, params.components)
+}
+`
+
+// overlay sets the source of a component's params and overlays the global
+// params on them, so each param shows its effective value.
+func (gp *GlobalParams) overlay(nsps []NamespaceParameter, source string) ([]NamespaceParameter, error) {
+	if len(nsps) == 0 {
+		return nsps, nil
+	}
+
+	var keys []string
+	for k := range gp.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var indices []string
+	positions := make(map[string]map[string]int)
+
+	var out []NamespaceParameter
+	for _, p := range nsps {
+		p.Source = source
+		if _, ok := positions[p.Index]; !ok {
+			positions[p.Index] = make(map[string]int)
+			indices = append(indices, p.Index)
+		}
+		positions[p.Index][p.Key] = len(out)
+		out = append(out, p)
+	}
+
+	for _, index := range indices {
+		for _, key := range keys {
+			value := gp.Values[key]
+
+			pos, ok := positions[index][key]
+			if !ok {
+				vStr, err := ParamValue(value)
+				if err != nil {
+					return nil, err
+				}
+
+				out = append(out, NamespaceParameter{
+					Component: nsps[0].Component,
+					Index:     index,
+					Key:       key,
+					Value:     vStr,
+					Source:    gp.Sources[key],
+				})
+				continue
+			}
+
+			if global, ok := value.(map[string]interface{}); ok {
+				var local map[string]interface{}
+				if err := json.Unmarshal([]byte(out[pos].Value), &local); err == nil && local != nil {
+					mergeGlobal(local, global)
+					value = local
+				}
+			}
+
+			vStr, err := ParamValue(value)
+			if err != nil {
+				return nil, err
+			}
+
+			out[pos].Value = vStr
+			out[pos].Source = gp.Sources[key]
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func withGlobals(t *testing.T, fn func(Namespace, afero.Fs)) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "globals/root.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "globals/infra.libsonnet", "/app/components/infra/params.libsonnet")
+	stageFile(t, fs, "globals/logging.libsonnet", "/app/components/infra/logging/params.libsonnet")
+	stageFile(t, fs, "globals/fluentd.jsonnet", "/app/components/infra/logging/fluentd.jsonnet")
+
+	fn(NewNamespace(app, "infra/logging"), fs)
+}
+
+func TestNamespace_Globals(t *testing.T) {
+	withGlobals(t, func(ns Namespace, fs afero.Fs) {
+		got, err := ns.Globals()
+		require.NoError(t, err)
+
+		expected := &GlobalParams{
+			Values: map[string]interface{}{
+				"registry": "infra.example.com",
+				"labels": map[string]interface{}{
+					"team": "platform",
+					"tier": "infra",
+					"app":  "logging",
+				},
+			},
+			Sources: map[string]string{
+				"registry": "infra",
+				"labels":   "infra/logging",
+			},
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestNamespace_Globals_missing_params(t *testing.T) {
+	withGlobals(t, func(ns Namespace, fs afero.Fs) {
+		require.NoError(t, fs.Remove("/app/components/infra/params.libsonnet"))
+
+		got, err := ns.Globals()
+		require.NoError(t, err)
+
+		require.Equal(t, "registry.example.com", got.Values["registry"])
+		require.Equal(t, "/", got.Sources["registry"])
+	})
+}
+
+func TestNamespace_ResolvedParams_cascade(t *testing.T) {
+	withGlobals(t, func(ns Namespace, fs afero.Fs) {
+		got, err := ns.ResolvedParams()
+		require.NoError(t, err)
+
+		expected := `{
+   "components": {
+      "fluentd": {
+         "image": "fluentd:v1",
+         "labels": {
+            "app": "logging",
+            "component": "fluentd",
+            "team": "platform",
+            "tier": "infra"
+         },
+         "registry": "infra.example.com"
+      }
+   }
+}
+`
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestNamespace_Params_sources(t *testing.T) {
+	withGlobals(t, func(ns Namespace, fs afero.Fs) {
+		got, err := ns.Params()
+		require.NoError(t, err)
+
+		expected := []NamespaceParameter{
+			{
+				Component: "fluentd",
+				Index:     "0",
+				Key:       "image",
+				Value:     `"fluentd:v1"`,
+				Source:    "infra/logging",
+			},
+			{
+				Component: "fluentd",
+				Index:     "0",
+				Key:       "labels",
+				Value:     `{"app":"logging","component":"fluentd","team":"platform","tier":"infra"}`,
+				Source:    "infra/logging",
+			},
+			{
+				Component: "fluentd",
+				Index:     "0",
+				Key:       "registry",
+				Value:     `"infra.example.com"`,
+				Source:    "infra",
+			},
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
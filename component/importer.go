@@ -0,0 +1,70 @@
+package component
+
+import (
+	"path"
+	"path/filepath"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Importer is a jsonnet importer for components. It provides the ksonnet
+// libraries for an environment from memory and resolves all other imports
+// from the app's file system.
+type Importer struct {
+	fs   afero.Fs
+	data map[string]string
+}
+
+var _ jsonnet.Importer = (*Importer)(nil)
+
+// NewImporter creates an instance of Importer for an environment.
+func NewImporter(a app.App, envName string) (*Importer, error) {
+	libPath, err := a.LibPath(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Importer{
+		fs:   a.Fs(),
+		data: make(map[string]string),
+	}
+
+	for _, name := range []string{"k.libsonnet", "k8s.libsonnet"} {
+		b, err := afero.ReadFile(a.Fs(), filepath.Join(libPath, name))
+		if err != nil {
+			return nil, err
+		}
+
+		i.data[name] = string(b)
+	}
+
+	return i, nil
+}
+
+// Add adds in memory content which can be imported with `importedPath`.
+func (i *Importer) Add(importedPath, content string) {
+	i.data[importedPath] = content
+}
+
+// Import imports `importedPath`. In memory content is preferred. Relative
+// paths are resolved from `codeDir`.
+func (i *Importer) Import(codeDir, importedPath string) (*jsonnet.ImportedData, error) {
+	if content, ok := i.data[importedPath]; ok {
+		return &jsonnet.ImportedData{Content: content, FoundHere: importedPath}, nil
+	}
+
+	p := importedPath
+	if !path.IsAbs(p) {
+		p = path.Join(codeDir, importedPath)
+	}
+
+	b, err := afero.ReadFile(i.fs, p)
+	if err != nil {
+		return nil, errors.Errorf("import not available %v", importedPath)
+	}
+
+	return &jsonnet.ImportedData{Content: string(b), FoundHere: p}, nil
+}
@@ -0,0 +1,79 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImporter_Import(t *testing.T) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "k.libsonnet", "/app/lib/v1.8.7/k.libsonnet")
+	stageFile(t, fs, "k8s.libsonnet", "/app/lib/v1.8.7/k8s.libsonnet")
+	require.NoError(t, afero.WriteFile(fs, "/app/components/lib.libsonnet", []byte("{}"), 0644))
+
+	importer, err := NewImporter(app, "default")
+	require.NoError(t, err)
+
+	importer.Add("memory.libsonnet", "{a: 1}")
+
+	cases := []struct {
+		name         string
+		codeDir      string
+		importedPath string
+		foundHere    string
+		content      string
+		isErr        bool
+	}{
+		{
+			name:         "ksonnet lib",
+			codeDir:      "/app/components",
+			importedPath: "k.libsonnet",
+			foundHere:    "k.libsonnet",
+			content:      string(testdata(t, "k.libsonnet")),
+		},
+		{
+			name:         "in memory",
+			codeDir:      "/app/components",
+			importedPath: "memory.libsonnet",
+			foundHere:    "memory.libsonnet",
+			content:      "{a: 1}",
+		},
+		{
+			name:         "relative",
+			codeDir:      "/app/components/_test",
+			importedPath: "../lib.libsonnet",
+			foundHere:    "/app/components/lib.libsonnet",
+			content:      "{}",
+		},
+		{
+			name:         "absolute",
+			codeDir:      "/",
+			importedPath: "/app/components/lib.libsonnet",
+			foundHere:    "/app/components/lib.libsonnet",
+			content:      "{}",
+		},
+		{
+			name:         "missing",
+			codeDir:      "/app/components",
+			importedPath: "missing.libsonnet",
+			isErr:        true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := importer.Import(tc.codeDir, tc.importedPath)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.foundHere, got.FoundHere)
+			require.Equal(t, tc.content, got.Content)
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ksonnet/ksonnet/metadata/app"
 
 	"github.com/bryanl/woowoo/params"
+	"github.com/bryanl/woowoo/pkg/util/native"
 	jsonnet "github.com/google/go-jsonnet"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -34,6 +35,7 @@ var _ Component = (*Jsonnet)(nil)
 func NewJsonnet(a app.App, nsName, source, paramsPath string) *Jsonnet {
 	return &Jsonnet{
 		app:        a,
+		nsName:     nsName,
 		source:     source,
 		paramsPath: paramsPath,
 	}
@@ -50,43 +52,6 @@ func (j *Jsonnet) Name(wantsNameSpaced bool) string {
 	return strings.TrimPrefix(path.Join(j.nsName, name), "/")
 }
 
-func (j *Jsonnet) vmImporter(envName string) (*jsonnet.MemoryImporter, error) {
-	libPath, err := j.app.LibPath(envName)
-	if err != nil {
-		return nil, err
-	}
-
-	readString := func(path string) (string, error) {
-		filename := filepath.Join(libPath, path)
-		var b []byte
-
-		b, err = afero.ReadFile(j.app.Fs(), filename)
-		if err != nil {
-			return "", err
-		}
-
-		return string(b), nil
-	}
-
-	dataK, err := readString("k.libsonnet")
-	if err != nil {
-		return nil, err
-	}
-	dataK8s, err := readString("k8s.libsonnet")
-	if err != nil {
-		return nil, err
-	}
-
-	importer := &jsonnet.MemoryImporter{
-		Data: map[string]string{
-			"k.libsonnet":   dataK,
-			"k8s.libsonnet": dataK8s,
-		},
-	}
-
-	return importer, nil
-}
-
 func jsonWalk(obj interface{}) ([]interface{}, error) {
 	switch o := obj.(type) {
 	case map[string]interface{}:
@@ -119,7 +84,7 @@ func jsonWalk(obj interface{}) ([]interface{}, error) {
 
 // Objects converts jsonnet to a slice of apimachinery unstructured objects.
 func (j *Jsonnet) Objects(paramsStr, envName string) ([]*unstructured.Unstructured, error) {
-	importer, err := j.vmImporter(envName)
+	importer, err := NewImporter(j.app, envName)
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +92,7 @@ func (j *Jsonnet) Objects(paramsStr, envName string) ([]*unstructured.Unstructur
 	vm := jsonnet.MakeVM()
 	vm.Importer(importer)
 	vm.ExtCode("__ksonnet/params", paramsStr)
+	native.Register(vm, j.app.Fs(), j.app.Root())
 
 	snippet, err := afero.ReadFile(j.app.Fs(), j.source)
 	if err != nil {
@@ -171,7 +137,7 @@ func (j *Jsonnet) SetParam(path []string, value interface{}, options ParamOption
 		return err
 	}
 
-	updatedParams, err := params.Set(path, paramsData, j.Name(false), value, paramsComponentRoot)
+	updatedParams, err := params.Set(path, paramsData, j.Name(false), value, paramsComponentRoot, params.SetWithType(options.Type))
 	if err != nil {
 		return err
 	}
@@ -216,7 +182,7 @@ func (j *Jsonnet) Params() ([]NamespaceParameter, error) {
 
 	var params []NamespaceParameter
 	for k, v := range props {
-		vStr, err := j.paramValue(v)
+		vStr, err := ParamValue(v)
 		if err != nil {
 			return nil, err
 		}
@@ -237,8 +203,17 @@ func (j *Jsonnet) Params() ([]NamespaceParameter, error) {
 	return params, nil
 }
 
-func (j *Jsonnet) paramValue(v interface{}) (string, error) {
-	switch v.(type) {
+// ParamValue formats a param value for display.
+func ParamValue(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case params.Computed:
+		s, err := ParamValue(t.Value)
+		if err != nil {
+			return "", err
+		}
+
+		expression := strings.Join(strings.Fields(t.Expression), " ")
+		return fmt.Sprintf("%s (= %s)", s, expression), nil
 	default:
 		s := fmt.Sprintf("%v", v)
 		return s, nil
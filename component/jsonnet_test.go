@@ -203,6 +203,37 @@ func TestJsonnet_Summarize(t *testing.T) {
 	require.Equal(t, expected, got)
 }
 
+func TestJsonnet_Objects_native(t *testing.T) {
+	app, fs := appMock("/app")
+
+	for _, file := range []string{"k.libsonnet", "k8s.libsonnet"} {
+		stageFile(t, fs, "guestbook/"+file, "/app/lib/v1.8.7/"+file)
+	}
+
+	src := `local config = std.native("parseYaml")(std.native("readFile")("config/app.yaml"))[0];
+
+{
+  apiVersion: "v1",
+  kind: "ConfigMap",
+  metadata: {name: config.name},
+  data: {checksum: std.native("sha256")(config.name)},
+}
+`
+	require.NoError(t, afero.WriteFile(fs, "/app/components/config.jsonnet", []byte(src), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/app/config/app.yaml", []byte("name: web\n"), 0644))
+
+	c := NewJsonnet(app, "", "/app/components/config.jsonnet", "/app/components/params.libsonnet")
+
+	list, err := c.Objects("{}", "default")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.Equal(t, "web", list[0].GetName())
+	require.Equal(t, map[string]interface{}{
+		"checksum": "4b5e57f6eb2f42b9039b3d1e13929295f231749c510cbe341cd68036d9af97e2",
+	}, list[0].Object["data"])
+}
+
 func TestJsonnet_SetParam(t *testing.T) {
 	app, fs := appMock("/")
 
@@ -3,6 +3,7 @@ package component
 import (
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/ksonnet/ksonnet/metadata/app"
 	"github.com/pkg/errors"
@@ -61,6 +62,10 @@ func (cpl *componentPathLocator) Locate() ([]string, error) {
 
 // isComponent reports if a file is a component. Components have a `jsonnet` extension.
 func isComponent(path string) bool {
+	if strings.HasSuffix(path, schemaExt) {
+		return false
+	}
+
 	for _, s := range []string{".jsonnet", ".yaml", "json"} {
 		if s == filepath.Ext(path) {
 			return true
@@ -25,6 +25,10 @@ func nsErrorMsg(format, nsName string) string {
 // Namespace is a component namespace.
 type Namespace struct {
 	path string
+	// envName is the environment whose overlay applies to the namespace.
+	envName string
+	// sealed keeps secrets encrypted in resolved params.
+	sealed bool
 
 	app app.App
 }
@@ -72,13 +76,13 @@ func (n *Namespace) ParamsPath() string {
 }
 
 // SetParam sets params for a namespace.
-func (n *Namespace) SetParam(path []string, value interface{}) error {
+func (n *Namespace) SetParam(path []string, value interface{}, opts ...params.SetOpt) error {
 	paramsData, err := n.readParams()
 	if err != nil {
 		return err
 	}
 
-	updatedParams, err := params.Set(path, paramsData, "", value, "global")
+	updatedParams, err := params.Set(path, paramsData, "", value, "global", opts...)
 	if err != nil {
 		return err
 	}
@@ -111,26 +115,52 @@ type NamespaceParameter struct {
 	Index     string
 	Key       string
 	Value     string
+	// Source is the namespace which set the value.
+	Source string
 }
 
 // ResolvedParams resolves paramaters for a namespace. It returns a JSON encoded
-// string of component parameters.
+// string of component parameters. Secrets are decrypted unless the namespace
+// is sealed.
 func (n *Namespace) ResolvedParams() (string, error) {
 	s, err := n.readParams()
 	if err != nil {
 		return "", err
 	}
 
-	return applyGlobals(s)
+	globals, err := n.Globals()
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := globals.applyTo(s)
+	if err != nil {
+		return "", err
+	}
+
+	if n.sealed {
+		return resolved, nil
+	}
+
+	return DecryptSecrets(n.app, resolved)
 }
 
-// Params returns the params for a namespace.
+// Params returns the effective params for a namespace. Global params from the
+// namespace and its parents are applied to each component's params.
 func (n *Namespace) Params() ([]NamespaceParameter, error) {
 	components, err := n.Components()
 	if err != nil {
 		return nil, err
 	}
 
+	globals, err := n.Globals()
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := n.lineage()
+	source := lineage[len(lineage)-1].Name()
+
 	var nsps []NamespaceParameter
 	for _, c := range components {
 		params, err := c.Params()
@@ -138,9 +168,12 @@ func (n *Namespace) Params() ([]NamespaceParameter, error) {
 			return nil, err
 		}
 
-		for _, p := range params {
-			nsps = append(nsps, p)
+		params, err = globals.overlay(params, source)
+		if err != nil {
+			return nil, err
 		}
+
+		nsps = append(nsps, params...)
 	}
 
 	return nsps, nil
@@ -175,7 +208,7 @@ func NamespacesFromEnv(a app.App, env string) ([]Namespace, error) {
 				return nil, err
 			}
 
-			namespaces = append(namespaces, ns)
+			namespaces = append(namespaces, ns.InEnvironment(env))
 		}
 	}
 
@@ -194,6 +227,19 @@ func Namespaces(a app.App) ([]Namespace, error) {
 		}
 
 		if fi.IsDir() {
+			if fi.Name() == TestDir {
+				return filepath.SkipDir
+			}
+
+			isChart, err := IsChartDir(a.Fs(), path)
+			if err != nil {
+				return err
+			}
+
+			if isChart {
+				return filepath.SkipDir
+			}
+
 			ok, err := isComponentDir(a.Fs(), path)
 			if err != nil {
 				return err
@@ -221,11 +267,104 @@ func Namespaces(a app.App) ([]Namespace, error) {
 	return namespaces, nil
 }
 
-// Components returns the components in a namespace.
+// Components returns the components in a namespace. If the namespace belongs
+// to an environment, components in the environment's overlay replace
+// same-named components, and patches in the overlay are added.
 func (n *Namespace) Components() ([]Component, error) {
+	components, err := n.componentsInDir(n.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, err := n.overlayComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, oc := range overlay {
+		replaced := false
+		if _, isPatch := oc.(Patcher); !isPatch {
+			for i := range components {
+				if _, isPatch := components[i].(Patcher); isPatch {
+					continue
+				}
+
+				if components[i].Name(false) == oc.Name(false) {
+					components[i] = oc
+					replaced = true
+				}
+			}
+		}
+
+		if !replaced {
+			components = append(components, oc)
+		}
+	}
+
+	return components, nil
+}
+
+// Sealed returns a copy of the namespace whose resolved params keep secrets
+// encrypted.
+func (n Namespace) Sealed() Namespace {
+	n.sealed = true
+	return n
+}
+
+// InEnvironment returns a copy of the namespace which includes the
+// components overlay for an environment.
+func (n Namespace) InEnvironment(envName string) Namespace {
+	n.envName = envName
+	return n
+}
+
+// OverlayDir is the directory containing the environment's overrides for the
+// namespace. It is empty if the namespace does not belong to an environment.
+func (n *Namespace) OverlayDir() string {
+	if n.envName == "" {
+		return ""
+	}
+
 	parts := strings.Split(n.path, "/")
-	nsDir := filepath.Join(append([]string{n.app.Root(), componentsRoot}, parts...)...)
+	path := []string{n.app.Root(), envRoot, n.envName, componentsRoot}
+	if len(n.path) != 0 {
+		path = append(path, parts...)
+	}
 
+	return filepath.Join(path...)
+}
+
+// Overridden returns the names of the components in the environment's
+// overlay for the namespace.
+func (n *Namespace) Overridden() (map[string]bool, error) {
+	overlay, err := n.overlayComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]bool)
+	for _, c := range overlay {
+		m[c.Name(false)] = true
+	}
+
+	return m, nil
+}
+
+func (n *Namespace) overlayComponents() ([]Component, error) {
+	dir := n.OverlayDir()
+	if dir == "" {
+		return nil, nil
+	}
+
+	exists, err := afero.DirExists(n.app.Fs(), dir)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	return n.componentsInDir(dir)
+}
+
+func (n *Namespace) componentsInDir(nsDir string) ([]Component, error) {
 	fis, err := afero.ReadDir(n.app.Fs(), nsDir)
 	if err != nil {
 		return nil, err
@@ -237,6 +376,30 @@ func (n *Namespace) Components() ([]Component, error) {
 		ext := filepath.Ext(fi.Name())
 		path := filepath.Join(nsDir, fi.Name())
 
+		if fi.IsDir() {
+			isChart, err := IsChartDir(n.app.Fs(), path)
+			if err != nil {
+				return nil, err
+			}
+
+			if isChart {
+				components = append(components, NewChart(n.app, n.Name(), path, n.ParamsPath()))
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(fi.Name(), schemaExt):
+			continue
+		case strings.HasSuffix(fi.Name(), templateExt):
+			ext = templateExt
+		case strings.HasSuffix(fi.Name(), patchExt):
+			ext = patchExt
+		case strings.HasSuffix(fi.Name(), generatorExt):
+			ext = generatorExt
+		}
+
 		switch ext {
 		// TODO: these should be constants
 		case ".yaml", ".json":
@@ -245,6 +408,13 @@ func (n *Namespace) Components() ([]Component, error) {
 		case ".jsonnet":
 			component := NewJsonnet(n.app, n.Name(), path, n.ParamsPath())
 			components = append(components, component)
+		case templateExt:
+			component := NewTemplate(n.app, n.Name(), path, n.ParamsPath())
+			components = append(components, component)
+		case patchExt:
+			components = append(components, NewPatch(n.app, n.Name(), path))
+		case generatorExt:
+			components = append(components, NewGenerator(n.app, n.Name(), path))
 		}
 	}
 
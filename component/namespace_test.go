@@ -13,6 +13,10 @@ func TestNamespace_Components(t *testing.T) {
 	stageFile(t, fs, "certificate-crd.yaml", "/app/components/ns1/certificate-crd.yaml")
 	stageFile(t, fs, "params-with-entry.libsonnet", "/app/components/ns1/params.libsonnet")
 	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/params.libsonnet")
+	stageFile(t, fs, "template/web.tmpl.yaml", "/app/components/ns2/web.tmpl.yaml")
+	stageFile(t, fs, "template/params.libsonnet", "/app/components/ns2/params.libsonnet")
+	stageFile(t, fs, "generator/db.generator.yaml", "/app/components/ns3/db.generator.yaml")
+	stageFile(t, fs, "params-no-entry.libsonnet", "/app/components/ns3/params.libsonnet")
 
 	cases := []struct {
 		name   string
@@ -28,6 +32,16 @@ func TestNamespace_Components(t *testing.T) {
 			nsName: "ns1",
 			count:  1,
 		},
+		{
+			name:   "with template components",
+			nsName: "ns2",
+			count:  1,
+		},
+		{
+			name:   "with generator components",
+			nsName: "ns3",
+			count:  1,
+		},
 	}
 
 	for _, tc := range cases {
@@ -45,3 +59,43 @@ func TestNamespace_Components(t *testing.T) {
 	}
 
 }
+
+func TestNamespace_Components_overlay(t *testing.T) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "certificate-crd.yaml", "/app/components/ns1/certificate-crd.yaml")
+	stageFile(t, fs, "template/web.tmpl.yaml", "/app/components/ns1/web.tmpl.yaml")
+	stageFile(t, fs, "params-with-entry.libsonnet", "/app/components/ns1/params.libsonnet")
+
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/environments/prod/components/ns1/certificate-crd.jsonnet")
+	stageFile(t, fs, "patch/redis.patch.yaml", "/app/environments/prod/components/ns1/web.patch.yaml")
+
+	ns, err := GetNamespace(app, "ns1")
+	require.NoError(t, err)
+
+	components, err := ns.Components()
+	require.NoError(t, err)
+	require.Len(t, components, 2)
+	assert.IsType(t, &YAML{}, components[0])
+
+	prod := ns.InEnvironment("prod")
+	assert.Equal(t, "/app/environments/prod/components/ns1", prod.OverlayDir())
+
+	components, err = prod.Components()
+	require.NoError(t, err)
+	require.Len(t, components, 3)
+
+	assert.IsType(t, &Jsonnet{}, components[0])
+	assert.Equal(t, "ns1/certificate-crd", components[0].Name(true))
+	assert.IsType(t, &Template{}, components[1])
+	assert.IsType(t, &Patch{}, components[2])
+
+	overridden, err := prod.Overridden()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"certificate-crd": true, "web": true}, overridden)
+
+	dev := ns.InEnvironment("dev")
+	components, err = dev.Components()
+	require.NoError(t, err)
+	require.Len(t, components, 2)
+}
@@ -1,25 +1,11 @@
 package component
 
 import (
+	"github.com/bryanl/woowoo/params"
 	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet/metadata/app"
 )
 
-func applyGlobals(params string) (string, error) {
-	vm := jsonnet.MakeVM()
-
-	vm.ExtCode("params", params)
-	return vm.EvaluateSnippet("snippet", snippetMapGlobal)
-}
-
-var snippetMapGlobal = `
-local params = std.extVar("params");
-local applyGlobal = function(key, value) std.mergePatch(value, params.global);
-
-{
-	components: std.mapWithKey(applyGlobal, params.components)
-}
-`
-
 type patchDoc struct {
 	Components map[string]interface{} `json:"components"`
 }
@@ -40,3 +26,18 @@ function(target, patch, patchName)
 	else
 		target
 `
+
+// DecryptSecrets decrypts the secrets in JSON encoded params with the app's
+// secret key. The key is only loaded if there are secrets.
+func DecryptSecrets(a app.App, s string) (string, error) {
+	if !params.HasSecrets(s) {
+		return s, nil
+	}
+
+	key, err := params.LoadSecretKey(a.Fs(), a.Root())
+	if err != nil {
+		return "", err
+	}
+
+	return key.DecryptJSON(s)
+}
@@ -7,11 +7,19 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func Test_applyGlobals(t *testing.T) {
+func TestGlobalParams_applyTo(t *testing.T) {
 	myParams, err := ioutil.ReadFile("testdata/params-global.libsonnet")
 	require.NoError(t, err)
 
-	got, err := applyGlobals(string(myParams))
+	gp := &GlobalParams{
+		Values: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{"global": "global"},
+			},
+		},
+	}
+
+	got, err := gp.applyTo(string(myParams))
 	require.NoError(t, err)
 
 	expected, err := ioutil.ReadFile("testdata/params-global-expected.json")
@@ -0,0 +1,236 @@
+package component
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bryanl/woowoo/pkg/util/patch"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// patchExt is the extension for patch components.
+	patchExt = ".patch.yaml"
+)
+
+// PatchTarget is an object rendered by a component which a patch can modify.
+type PatchTarget struct {
+	// Component is the component which rendered the object.
+	Component Component
+	// Object is the rendered object.
+	Object *unstructured.Unstructured
+}
+
+// Patcher is a component which modifies the objects rendered by the other
+// components in its namespace.
+type Patcher interface {
+	Component
+
+	// Patch patches targets for an environment.
+	Patch(envName string, targets []PatchTarget) error
+}
+
+// PatchFile is the contents of a patch component.
+//
+//	environments: [prod]
+//	patches:
+//	- target:
+//	    kind: Deployment
+//	    name: redis
+//	  strategic:
+//	    spec:
+//	      replicas: 3
+//	- target:
+//	    component: redis
+//	  json:
+//	  - op: add
+//	    path: /metadata/labels/tier
+//	    value: cache
+type PatchFile struct {
+	// Environments are the environments the patches apply to. The patches
+	// apply to all environments if it is empty.
+	Environments []string `json:"environments,omitempty"`
+	// Patches are the patches.
+	Patches []PatchSpec `json:"patches"`
+}
+
+// PatchSpec is a patch and the objects it targets.
+type PatchSpec struct {
+	Target PatchSelector `json:"target"`
+	// Strategic is a strategic merge patch.
+	Strategic map[string]interface{} `json:"strategic,omitempty"`
+	// JSON is a list of JSON patch operations.
+	JSON []patch.Operation `json:"json,omitempty"`
+}
+
+// PatchSelector selects objects. A selector with no fields selects the
+// objects of the component with the same name as the patch.
+type PatchSelector struct {
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Component is the name of the component which rendered the object.
+	Component string `json:"component,omitempty"`
+}
+
+func (ps *PatchSelector) matches(target PatchTarget) bool {
+	if ps.Kind != "" && ps.Kind != target.Object.GetKind() {
+		return false
+	}
+
+	if ps.Name != "" && ps.Name != target.Object.GetName() {
+		return false
+	}
+
+	if ps.Component != "" &&
+		ps.Component != target.Component.Name(false) &&
+		ps.Component != target.Component.Name(true) {
+		return false
+	}
+
+	return true
+}
+
+// Patch is a component which patches the objects rendered by the other
+// components in its namespace. It renders no objects itself.
+type Patch struct {
+	app    app.App
+	nsName string
+	source string
+}
+
+var _ Patcher = (*Patch)(nil)
+
+// NewPatch creates an instance of Patch.
+func NewPatch(a app.App, nsName, source string) *Patch {
+	return &Patch{
+		app:    a,
+		nsName: nsName,
+		source: source,
+	}
+}
+
+// Name is the name of this component.
+func (p *Patch) Name(wantsNameSpaced bool) string {
+	name := strings.TrimSuffix(filepath.Base(p.source), patchExt)
+	if !wantsNameSpaced {
+		return name
+	}
+
+	return strings.TrimPrefix(path.Join(p.nsName, name), "/")
+}
+
+// Objects returns no objects. Patches modify the objects of other components.
+func (p *Patch) Objects(paramsStr, envName string) ([]*unstructured.Unstructured, error) {
+	return []*unstructured.Unstructured{}, nil
+}
+
+// Patch applies the patches to targets if they apply to the environment.
+func (p *Patch) Patch(envName string, targets []PatchTarget) error {
+	pf, err := p.read()
+	if err != nil {
+		return err
+	}
+
+	if len(pf.Environments) > 0 && !stringInSlice(envName, pf.Environments) {
+		return nil
+	}
+
+	for i, spec := range pf.Patches {
+		selector := spec.Target
+		if selector == (PatchSelector{}) {
+			selector.Component = p.Name(false)
+		}
+
+		for _, target := range targets {
+			if !selector.matches(target) {
+				continue
+			}
+
+			if spec.Strategic != nil {
+				err = patch.StrategicMerge(target.Object.Object, spec.Strategic)
+			} else {
+				err = patch.JSON(target.Object.Object, spec.JSON)
+			}
+
+			if err != nil {
+				return errors.Wrapf(err, "apply patch %d in %s to %s/%s",
+					i, p.Name(true), target.Object.GetKind(), target.Object.GetName())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *Patch) read() (*PatchFile, error) {
+	b, err := afero.ReadFile(p.app.Fs(), p.source)
+	if err != nil {
+		return nil, err
+	}
+
+	var pf PatchFile
+	if err := yaml.Unmarshal(b, &pf); err != nil {
+		return nil, errors.Wrapf(err, "decode patch %s", p.Name(true))
+	}
+
+	for i, spec := range pf.Patches {
+		if (spec.Strategic == nil) == (spec.JSON == nil) {
+			return nil, errors.Errorf("patch %d in %s must have exactly one of strategic or json", i, p.Name(true))
+		}
+	}
+
+	return &pf, nil
+}
+
+// SetParam returns an error since patches do not have params.
+func (p *Patch) SetParam(path []string, value interface{}, options ParamOptions) error {
+	return errors.Errorf("patch component %s does not have params", p.Name(true))
+}
+
+// DeleteParam returns an error since patches do not have params.
+func (p *Patch) DeleteParam(path []string, options ParamOptions) error {
+	return errors.Errorf("patch component %s does not have params", p.Name(true))
+}
+
+// Params returns no params since patches do not have params.
+func (p *Patch) Params() ([]NamespaceParameter, error) {
+	return nil, nil
+}
+
+// Summarize creates a summary for each patch in the component.
+func (p *Patch) Summarize() ([]Summary, error) {
+	pf, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for i, spec := range pf.Patches {
+		summaries = append(summaries, Summary{
+			ComponentName: p.Name(false),
+			IndexStr:      strconv.Itoa(i),
+			Index:         i,
+			Type:          "patch",
+			Kind:          spec.Target.Kind,
+			Name:          spec.Target.Name,
+		})
+	}
+
+	return summaries, nil
+}
+
+func stringInSlice(s string, sl []string) bool {
+	for i := range sl {
+		if sl[i] == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,148 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func withPatch(t *testing.T, fn func(*Patch, afero.Fs)) {
+	app, fs := appMock("/")
+
+	stageFile(t, fs, "patch/redis.patch.yaml", "/components/redis.patch.yaml")
+
+	p := NewPatch(app, "/", "/components/redis.patch.yaml")
+
+	fn(p, fs)
+}
+
+func patchTargets() []PatchTarget {
+	app, _ := appMock("/")
+
+	redis := NewJsonnet(app, "/", "/components/redis.jsonnet", "/components/params.libsonnet")
+	other := NewJsonnet(app, "/", "/components/other.jsonnet", "/components/params.libsonnet")
+
+	return []PatchTarget{
+		{
+			Component: redis,
+			Object: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":     "Deployment",
+					"metadata": map[string]interface{}{"name": "redis"},
+					"spec": map[string]interface{}{
+						"replicas": int64(1),
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{"name": "redis", "image": "redis:4.0.9"},
+									map[string]interface{}{"name": "exporter", "image": "exporter:1"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Component: other,
+			Object: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":     "Service",
+					"metadata": map[string]interface{}{"name": "other"},
+				},
+			},
+		},
+	}
+}
+
+func TestPatch_Name(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		require.Equal(t, "redis", p.Name(false))
+		require.Equal(t, "redis", p.Name(true))
+	})
+}
+
+func TestPatch_Objects(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		got, err := p.Objects("", "prod")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+}
+
+func TestPatch_Patch(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		targets := patchTargets()
+
+		require.NoError(t, p.Patch("prod", targets))
+
+		deployment := targets[0].Object
+		spec := deployment.Object["spec"].(map[string]interface{})
+		require.Equal(t, float64(3), spec["replicas"])
+
+		containers := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"name": "redis", "image": "redis:4.0.10"},
+			map[string]interface{}{"name": "exporter", "image": "exporter:1"},
+		}, containers)
+
+		require.Equal(t, map[string]string{"tier": "cache"}, deployment.GetLabels())
+
+		require.Equal(t, patchTargets()[1].Object, targets[1].Object)
+	})
+}
+
+func TestPatch_Patch_other_environment(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		targets := patchTargets()
+
+		require.NoError(t, p.Patch("default", targets))
+		require.Equal(t, patchTargets()[0].Object, targets[0].Object)
+	})
+}
+
+func TestPatch_Patch_invalid(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		src := []byte("patches:\n- target:\n    kind: Service\n")
+		require.NoError(t, afero.WriteFile(fs, "/components/redis.patch.yaml", src, 0644))
+
+		err := p.Patch("prod", patchTargets())
+		require.Error(t, err)
+	})
+}
+
+func TestPatch_SetParam(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		err := p.SetParam([]string{"replicas"}, 3, ParamOptions{})
+		require.Error(t, err)
+	})
+}
+
+func TestPatch_Summarize(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		got, err := p.Summarize()
+		require.NoError(t, err)
+
+		expected := []Summary{
+			{ComponentName: "redis", IndexStr: "0", Index: 0, Type: "patch", Kind: "Deployment", Name: "redis"},
+			{ComponentName: "redis", IndexStr: "1", Index: 1, Type: "patch"},
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestPatch_Patch_default_target(t *testing.T) {
+	withPatch(t, func(p *Patch, fs afero.Fs) {
+		src := []byte("patches:\n- strategic:\n    metadata:\n      labels:\n        patched: \"true\"\n")
+		require.NoError(t, afero.WriteFile(fs, "/components/redis.patch.yaml", src, 0644))
+
+		targets := patchTargets()
+		require.NoError(t, p.Patch("default", targets))
+
+		require.Equal(t, map[string]string{"patched": "true"}, targets[0].Object.GetLabels())
+		require.Empty(t, targets[1].Object.GetLabels())
+	})
+}
@@ -0,0 +1,44 @@
+package component
+
+import (
+	"path/filepath"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// schemaExt is the extension for a component's params schema. The schema
+	// for `web.jsonnet` is `web.schema.json`.
+	schemaExt = ".schema.json"
+)
+
+// LoadSchema loads the params schema for a component. It returns nil if the
+// component does not declare a schema.
+func LoadSchema(a app.App, c Component) (*params.Schema, error) {
+	ns, name := ExtractNamespacedComponent(a, c.Name(true))
+	path := filepath.Join(ns.Dir(), name+schemaExt)
+
+	exists, err := afero.Exists(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	b, err := afero.ReadFile(a.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := params.ParseSchema(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load schema for %s", c.Name(true))
+	}
+
+	return s, nil
+}
@@ -0,0 +1,40 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSchema(t *testing.T) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/ns1/guestbook-ui.jsonnet")
+	stageFile(t, fs, "guestbook/params.libsonnet", "/app/components/ns1/params.libsonnet")
+	stageFile(t, fs, "schema/guestbook-ui.schema.json", "/app/components/ns1/guestbook-ui.schema.json")
+
+	ns, err := GetNamespace(app, "ns1")
+	require.NoError(t, err)
+
+	components, err := ns.Components()
+	require.NoError(t, err)
+	require.Len(t, components, 1)
+
+	s, err := LoadSchema(app, components[0])
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, []string{"image"}, s.Required)
+	require.Equal(t, "container image", s.Properties["image"].Description)
+}
+
+func TestLoadSchema_missing(t *testing.T) {
+	app, fs := appMock("/app")
+
+	stageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/guestbook-ui.jsonnet")
+
+	c := NewJsonnet(app, "", "/app/components/guestbook-ui.jsonnet", "/app/components/params.libsonnet")
+
+	s, err := LoadSchema(app, c)
+	require.NoError(t, err)
+	require.Nil(t, s)
+}
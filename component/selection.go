@@ -0,0 +1,124 @@
+package component
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+)
+
+// Selection includes and excludes individual components for an environment.
+// It is kept in the environment spec.
+// Environment targets select whole namespaces. Included components are
+// rendered even if their namespace is not a target, and excluded components
+// are never rendered. Components are identified by their namespaced name.
+type Selection struct {
+	Include []string
+	Exclude []string
+}
+
+// ReadSelection reads the component selection from an environment's spec.
+func ReadSelection(a app.App, envName string) (*Selection, error) {
+	spec, err := a.Environment(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Selection{Include: spec.Include, Exclude: spec.Exclude}, nil
+}
+
+// WriteSelection writes the component selection to an environment's spec.
+func WriteSelection(a app.App, envName string, s *Selection) error {
+	if _, ok := a.(*app.App001); ok {
+		return errors.New("ks apps with version 0.0.1 do not have support for component selections")
+	}
+
+	spec, err := a.Environment(envName)
+	if err != nil {
+		return err
+	}
+
+	spec.Include = s.Include
+	spec.Exclude = s.Exclude
+
+	// adding an existing environment replaces its spec
+	return a.AddEnvironment(envName, "", spec)
+}
+
+// Enable includes a component.
+func (s *Selection) Enable(name string) {
+	name = normalizeComponentName(name)
+	s.Exclude = removeString(s.Exclude, name)
+	s.Include = addString(s.Include, name)
+}
+
+// Disable excludes a component.
+func (s *Selection) Disable(name string) {
+	name = normalizeComponentName(name)
+	s.Include = removeString(s.Include, name)
+	s.Exclude = addString(s.Exclude, name)
+}
+
+// IsEmpty reports if the selection neither includes nor excludes components.
+func (s *Selection) IsEmpty() bool {
+	return len(s.Include) == 0 && len(s.Exclude) == 0
+}
+
+// IsIncluded reports if a component is included.
+func (s *Selection) IsIncluded(name string) bool {
+	return stringInSlice(normalizeComponentName(name), s.Include)
+}
+
+// IsExcluded reports if a component is excluded.
+func (s *Selection) IsExcluded(name string) bool {
+	return stringInSlice(normalizeComponentName(name), s.Exclude)
+}
+
+// IncludedNamespaces returns the names of the namespaces of the included
+// components.
+func (s *Selection) IncludedNamespaces() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range s.Include {
+		nsName := ""
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			nsName = name[:i]
+		}
+
+		if !seen[nsName] {
+			seen[nsName] = true
+			names = append(names, nsName)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// normalizeComponentName removes leading slashes, so `/ns/web` and `ns/web`
+// are the same component.
+func normalizeComponentName(name string) string {
+	return strings.TrimLeft(name, "/")
+}
+
+func addString(sl []string, s string) []string {
+	if stringInSlice(s, sl) {
+		return sl
+	}
+
+	sl = append(sl, s)
+	sort.Strings(sl)
+	return sl
+}
+
+func removeString(sl []string, s string) []string {
+	var out []string
+	for _, item := range sl {
+		if item != s {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,107 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const selectionAppYAML = `apiVersion: 0.1.0
+kind: ksonnet.io/app
+name: app
+version: 0.0.1
+environments:
+  default:
+    destination:
+      namespace: default
+      server: http://example.com
+    k8sVersion: v1.8.7
+    path: default
+`
+
+func selectionApp(t *testing.T) (app.App, afero.Fs) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/app/app.yaml", []byte(selectionAppYAML), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/app/environments/default/main.jsonnet", []byte("{}"), 0644))
+
+	a, err := app.Load(fs, "/app")
+	require.NoError(t, err)
+
+	return a, fs
+}
+
+func TestReadSelection_missing(t *testing.T) {
+	a, _ := selectionApp(t)
+
+	got, err := ReadSelection(a, "default")
+	require.NoError(t, err)
+
+	require.True(t, got.IsEmpty())
+}
+
+func TestSelection_roundTrip(t *testing.T) {
+	a, fs := selectionApp(t)
+
+	s := &Selection{}
+	s.Enable("/ns1/web")
+	s.Disable("db")
+
+	require.NoError(t, WriteSelection(a, "default", s))
+
+	expected := &Selection{
+		Include: []string{"ns1/web"},
+		Exclude: []string{"db"},
+	}
+
+	// the selection is kept in app.yaml
+	a, err := app.Load(fs, "/app")
+	require.NoError(t, err)
+
+	spec, err := a.Environment("default")
+	require.NoError(t, err)
+	require.Equal(t, expected.Include, spec.Include)
+	require.Equal(t, expected.Exclude, spec.Exclude)
+
+	got, err := ReadSelection(a, "default")
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// environments keep their selection when they are renamed
+	require.NoError(t, a.RenameEnvironment("default", "prod"))
+
+	a, err = app.Load(fs, "/app")
+	require.NoError(t, err)
+
+	got, err = ReadSelection(a, "prod")
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+
+	// and lose it when they are removed
+	require.NoError(t, a.RemoveEnvironment("prod"))
+
+	_, err = ReadSelection(a, "prod")
+	require.Error(t, err)
+}
+
+func TestSelection(t *testing.T) {
+	s := &Selection{}
+	s.Enable("ns1/web")
+	s.Enable("ns1/nested/api")
+	s.Enable("cache")
+	s.Disable("db")
+
+	require.True(t, s.IsIncluded("/ns1/web"))
+	require.False(t, s.IsExcluded("ns1/web"))
+	require.True(t, s.IsExcluded("/db"))
+	require.Equal(t, []string{"", "ns1", "ns1/nested"}, s.IncludedNamespaces())
+
+	s.Disable("ns1/web")
+	require.False(t, s.IsIncluded("ns1/web"))
+	require.True(t, s.IsExcluded("ns1/web"))
+
+	s.Enable("db")
+	require.True(t, s.IsIncluded("db"))
+	require.False(t, s.IsExcluded("db"))
+}
@@ -0,0 +1,288 @@
+package component
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bryanl/woowoo/k8sutil"
+	"github.com/bryanl/woowoo/params"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	amyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// templateExt is the extension for template components.
+	templateExt = ".tmpl.yaml"
+)
+
+// TemplateData is the data a template component is rendered with.
+type TemplateData struct {
+	// Values are the component's params.
+	Values map[string]interface{}
+	// Component is the name of the component.
+	Component string
+	// Namespace is the name of the component namespace.
+	Namespace string
+	// Environment is the name of the environment being rendered.
+	Environment string
+}
+
+// Template is a component based on a Go text/template which renders YAML.
+type Template struct {
+	app        app.App
+	nsName     string
+	source     string
+	paramsPath string
+}
+
+var _ Component = (*Template)(nil)
+
+// NewTemplate creates an instance of Template.
+func NewTemplate(a app.App, nsName, source, paramsPath string) *Template {
+	return &Template{
+		app:        a,
+		nsName:     nsName,
+		source:     source,
+		paramsPath: paramsPath,
+	}
+}
+
+// Name is the name of this component.
+func (t *Template) Name(wantsNameSpaced bool) string {
+	name := strings.TrimSuffix(filepath.Base(t.source), templateExt)
+	if !wantsNameSpaced {
+		return name
+	}
+
+	return strings.TrimPrefix(path.Join(t.nsName, name), "/")
+}
+
+// Objects renders the template to a slice of apimachinery unstructured
+// objects. The template's `.Values` are the component's entry in the resolved
+// params.
+func (t *Template) Objects(paramsStr, envName string) ([]*unstructured.Unstructured, error) {
+	if paramsStr == "" {
+		paramsData, err := t.readParams()
+		if err != nil {
+			return nil, err
+		}
+
+		paramsStr, err = resolveParams(t.app, t.nsName, paramsData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := t.values(paramsStr)
+	if err != nil {
+		return nil, err
+	}
+
+	data := TemplateData{
+		Values:      values,
+		Component:   t.Name(false),
+		Namespace:   t.nsName,
+		Environment: envName,
+	}
+
+	rendered, err := t.render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := decodeYAMLStream(rendered)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode rendered template %s", t.Name(true))
+	}
+
+	return k8sutil.FlattenToV1(objects)
+}
+
+func (t *Template) values(paramsStr string) (map[string]interface{}, error) {
+	var doc patchDoc
+	if err := json.Unmarshal([]byte(paramsStr), &doc); err != nil {
+		return nil, errors.Wrap(err, "decode params")
+	}
+
+	values, ok := doc.Components[t.Name(false)].(map[string]interface{})
+	if !ok {
+		values = make(map[string]interface{})
+	}
+
+	return values, nil
+}
+
+func (t *Template) render(data TemplateData) ([]byte, error) {
+	src, err := afero.ReadFile(t.app.Fs(), t.source)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(filepath.Base(t.source)).
+		Option("missingkey=zero").
+		Funcs(templateFuncs()).
+		Parse(string(src))
+	if err != nil {
+		return nil, errors.Wrap(err, "parse template")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, errors.Wrap(err, "render template")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeYAMLStream decodes a stream of YAML documents. Empty documents are
+// skipped.
+func decodeYAMLStream(b []byte) ([]runtime.Object, error) {
+	decoder := amyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+
+	var ret []runtime.Object
+	for {
+		doc, err := decoder.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		jsondata, err := amyaml.ToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if string(jsondata) == "null" {
+			continue
+		}
+
+		obj, _, err := unstructured.UnstructuredJSONScheme.Decode(jsondata, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, obj)
+	}
+
+	return ret, nil
+}
+
+// SetParam set parameter for a component.
+func (t *Template) SetParam(path []string, value interface{}, options ParamOptions) error {
+	paramsData, err := t.readParams()
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Set(path, paramsData, t.Name(false), value, paramsComponentRoot, params.SetWithType(options.Type))
+	if err != nil {
+		return err
+	}
+
+	return t.writeParams(updatedParams)
+}
+
+// DeleteParam deletes a param.
+func (t *Template) DeleteParam(path []string, options ParamOptions) error {
+	paramsData, err := t.readParams()
+	if err != nil {
+		return err
+	}
+
+	updatedParams, err := params.Delete(path, paramsData, t.Name(false), paramsComponentRoot)
+	if err != nil {
+		return err
+	}
+
+	return t.writeParams(updatedParams)
+}
+
+// Params returns params for a component.
+func (t *Template) Params() ([]NamespaceParameter, error) {
+	paramsData, err := t.readParams()
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := params.ToMap(t.Name(false), paramsData, paramsComponentRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find components")
+	}
+
+	var params []NamespaceParameter
+	for k, v := range props {
+		vStr, err := ParamValue(v)
+		if err != nil {
+			return nil, err
+		}
+
+		np := NamespaceParameter{
+			Component: t.Name(false),
+			Key:       k,
+			Index:     "0",
+			Value:     vStr,
+		}
+
+		params = append(params, np)
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Key < params[j].Key
+	})
+
+	return params, nil
+}
+
+// Summarize creates a summary for the component. The template is rendered
+// with the namespace's params to find the objects it creates.
+func (t *Template) Summarize() ([]Summary, error) {
+	objects, err := t.Objects("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for i, obj := range objects {
+		summaries = append(summaries, Summary{
+			ComponentName: t.Name(false),
+			IndexStr:      strconv.Itoa(i),
+			Index:         i,
+			Type:          "template",
+			APIVersion:    obj.GetAPIVersion(),
+			Kind:          obj.GetKind(),
+			Name:          obj.GetName(),
+		})
+	}
+
+	return summaries, nil
+}
+
+func (t *Template) readParams() (string, error) {
+	b, err := afero.ReadFile(t.app.Fs(), t.paramsPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (t *Template) writeParams(src string) error {
+	return afero.WriteFile(t.app.Fs(), t.paramsPath, []byte(src), 0644)
+}
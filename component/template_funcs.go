@@ -0,0 +1,251 @@
+package component
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// templateFuncs are the functions available to template components. They
+// follow the names and argument order of the sprig functions used by Helm
+// charts, so charts can be brought over with few changes.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// defaults
+		"default":  tmplDefault,
+		"empty":    tmplEmpty,
+		"coalesce": tmplCoalesce,
+		"ternary":  tmplTernary,
+		"required": tmplRequired,
+
+		// strings
+		"quote":      tmplQuote,
+		"squote":     tmplSquote,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      strings.Title,
+		"trim":       strings.TrimSpace,
+		"trunc":      tmplTrunc,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       tmplJoin,
+		"indent":     tmplIndent,
+		"nindent":    func(n int, s string) string { return "\n" + tmplIndent(n, s) },
+		"toString":   tmplToString,
+
+		// conversion and encoding
+		"int":       tmplInt,
+		"toJson":    tmplToJSON,
+		"toYaml":    tmplToYAML,
+		"b64enc":    func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":    tmplB64Dec,
+		"sha256sum": tmplSha256Sum,
+
+		// collections
+		"list": func(items ...interface{}) []interface{} { return items },
+		"dict": tmplDict,
+	}
+}
+
+func tmplDefault(d interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || tmplEmpty(given[0]) {
+		return d
+	}
+
+	return given[0]
+}
+
+// tmplEmpty reports if a value is the zero value for its type.
+func tmplEmpty(given interface{}) bool {
+	v := reflect.ValueOf(given)
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+func tmplCoalesce(values ...interface{}) interface{} {
+	for _, v := range values {
+		if !tmplEmpty(v) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func tmplTernary(vt, vf interface{}, condition bool) interface{} {
+	if condition {
+		return vt
+	}
+
+	return vf
+}
+
+func tmplRequired(message string, v interface{}) (interface{}, error) {
+	if tmplEmpty(v) {
+		return nil, errors.New(message)
+	}
+
+	return v, nil
+}
+
+func tmplQuote(values ...interface{}) string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+
+		out = append(out, strconv.Quote(tmplToString(v)))
+	}
+
+	return strings.Join(out, " ")
+}
+
+func tmplSquote(values ...interface{}) string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+
+		out = append(out, "'"+tmplToString(v)+"'")
+	}
+
+	return strings.Join(out, " ")
+}
+
+func tmplJoin(sep string, v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return tmplToString(v)
+	}
+
+	out := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		out = append(out, tmplToString(rv.Index(i).Interface()))
+	}
+
+	return strings.Join(out, sep)
+}
+
+func tmplTrunc(n int, s string) string {
+	if n >= 0 && len(s) > n {
+		return s[:n]
+	}
+
+	return s
+}
+
+func tmplIndent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	return pad + strings.Replace(s, "\n", "\n"+pad, -1)
+}
+
+func tmplToString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case nil:
+		return ""
+	case float64:
+		// params decoded from JSON are float64, but integers should render
+		// without an exponent or decimal point.
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func tmplInt(v interface{}) (int, error) {
+	switch t := v.(type) {
+	case int:
+		return t, nil
+	case int64:
+		return int(t), nil
+	case float64:
+		return int(t), nil
+	case string:
+		return strconv.Atoi(t)
+	default:
+		return 0, errors.Errorf("unable to convert %v to int", v)
+	}
+}
+
+func tmplToJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func tmplToYAML(v interface{}) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(b), "\n"), nil
+}
+
+func tmplB64Dec(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func tmplSha256Sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func tmplDict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict requires an even number of arguments")
+	}
+
+	m := make(map[string]interface{})
+	for i := 0; i < len(pairs); i += 2 {
+		m[tmplToString(pairs[i])] = pairs[i+1]
+	}
+
+	return m, nil
+}
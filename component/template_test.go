@@ -0,0 +1,220 @@
+package component
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func withTemplate(t *testing.T, fn func(*Template, afero.Fs)) {
+	app, fs := appMock("/")
+
+	for _, file := range []string{"web.tmpl.yaml", "params.libsonnet"} {
+		stageFile(t, fs, "template/"+file, "/components/ns1/"+file)
+	}
+
+	c := NewTemplate(app, "ns1", "/components/ns1/web.tmpl.yaml", "/components/ns1/params.libsonnet")
+
+	fn(c, fs)
+}
+
+func TestTemplate_Name(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		require.Equal(t, "web", c.Name(false))
+		require.Equal(t, "ns1/web", c.Name(true))
+	})
+}
+
+func TestTemplate_Objects(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		paramsStr := `{"components": {"web": {"name": "web", "port": 8080, "image": "nginx:1.13", "replicas": 3}}}`
+
+		list, err := c.Objects(paramsStr, "default")
+		require.NoError(t, err)
+
+		expected := []*unstructured.Unstructured{
+			{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"name":   "web",
+						"labels": map[string]interface{}{"env": "default"},
+					},
+					"spec": map[string]interface{}{
+						"ports": []interface{}{
+							map[string]interface{}{"port": int64(8080)},
+						},
+						"selector": map[string]interface{}{"app": "web"},
+					},
+				},
+			},
+			{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1beta1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"name": "web",
+					},
+					"spec": map[string]interface{}{
+						"replicas": int64(3),
+						"template": map[string]interface{}{
+							"metadata": map[string]interface{}{
+								"labels": map[string]interface{}{"app": "web"},
+							},
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name":  "web",
+										"image": "nginx:1.13",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		require.Equal(t, expected, list)
+	})
+}
+
+func TestTemplate_Objects_namespace_params(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		list, err := c.Objects("", "default")
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+
+		template := list[1].Object["spec"].(map[string]interface{})["template"].(map[string]interface{})
+		labels := template["metadata"].(map[string]interface{})["labels"]
+		containers := template["spec"].(map[string]interface{})["containers"].([]interface{})
+
+		require.Equal(t, map[string]interface{}{"app": "web", "tier": "frontend"}, labels)
+		require.Equal(t, "nginx:1.13", containers[0].(map[string]interface{})["image"])
+	})
+}
+
+func TestTemplate_Objects_invalid_template(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		err := afero.WriteFile(fs, "/components/ns1/web.tmpl.yaml", []byte("name: {{ .Values.name"), 0644)
+		require.NoError(t, err)
+
+		_, err = c.Objects(`{"components": {}}`, "default")
+		require.Error(t, err)
+	})
+}
+
+func TestTemplate_Params(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		got, err := c.Params()
+		require.NoError(t, err)
+
+		expected := []NamespaceParameter{
+			{Component: "web", Index: "0", Key: "labels", Value: `{"tier":"frontend"}`},
+			{Component: "web", Index: "0", Key: "name", Value: `"web"`},
+			{Component: "web", Index: "0", Key: "port", Value: "8080"},
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestTemplate_SetParam(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		err := c.SetParam([]string{"debug"}, true, ParamOptions{})
+		require.NoError(t, err)
+
+		b, err := afero.ReadFile(fs, "/components/ns1/params.libsonnet")
+		require.NoError(t, err)
+
+		m, err := params.ToMap("web", string(b), paramsComponentRoot)
+		require.NoError(t, err)
+		require.Equal(t, true, m["debug"])
+
+		list, err := c.Objects("", "default")
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+	})
+}
+
+func TestTemplate_DeleteParam(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		err := c.DeleteParam([]string{"labels"}, ParamOptions{})
+		require.NoError(t, err)
+
+		b, err := afero.ReadFile(fs, "/components/ns1/params.libsonnet")
+		require.NoError(t, err)
+
+		m, err := params.ToMap("web", string(b), paramsComponentRoot)
+		require.NoError(t, err)
+		require.NotContains(t, m, "labels")
+	})
+}
+
+func TestTemplate_Summarize(t *testing.T) {
+	withTemplate(t, func(c *Template, fs afero.Fs) {
+		got, err := c.Summarize()
+		require.NoError(t, err)
+
+		expected := []Summary{
+			{ComponentName: "web", IndexStr: "0", Index: 0, Type: "template", APIVersion: "v1", Kind: "Service", Name: "web"},
+			{ComponentName: "web", IndexStr: "1", Index: 1, Type: "template", APIVersion: "apps/v1beta1", Kind: "Deployment", Name: "web"},
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
+
+func Test_templateFuncs(t *testing.T) {
+	cases := []struct {
+		name     string
+		tmpl     string
+		data     interface{}
+		expected string
+		isErr    bool
+	}{
+		{name: "default with value", tmpl: `{{ default "a" "b" }}`, expected: "b"},
+		{name: "default empty", tmpl: `{{ default "a" "" }}`, expected: "a"},
+		{name: "default missing", tmpl: `{{ default 3 .missing }}`, data: map[string]interface{}{}, expected: "3"},
+		{name: "quote", tmpl: `{{ quote "a" }}`, expected: `"a"`},
+		{name: "squote number", tmpl: `{{ squote 8080.0 }}`, expected: `'8080'`},
+		{name: "upper", tmpl: `{{ upper "a" }}`, expected: "A"},
+		{name: "trimSuffix", tmpl: `{{ trimSuffix "-x" "a-x" }}`, expected: "a"},
+		{name: "replace", tmpl: `{{ "a.b.c" | replace "." "-" }}`, expected: "a-b-c"},
+		{name: "join", tmpl: `{{ list "a" "b" | join "," }}`, expected: "a,b"},
+		{name: "indent", tmpl: `{{ indent 2 "a\nb" }}`, expected: "  a\n  b"},
+		{name: "nindent", tmpl: `{{ nindent 2 "a" }}`, expected: "\n  a"},
+		{name: "toJson", tmpl: `{{ dict "a" 1 | toJson }}`, expected: `{"a":1}`},
+		{name: "toYaml", tmpl: `{{ dict "a" 1 | toYaml }}`, expected: "a: 1"},
+		{name: "b64enc", tmpl: `{{ b64enc "hello" }}`, expected: "aGVsbG8="},
+		{name: "b64dec", tmpl: `{{ b64dec "aGVsbG8=" }}`, expected: "hello"},
+		{name: "sha256sum", tmpl: `{{ sha256sum "a" }}`, expected: "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"},
+		{name: "ternary", tmpl: `{{ ternary "yes" "no" true }}`, expected: "yes"},
+		{name: "coalesce", tmpl: `{{ coalesce "" "b" }}`, expected: "b"},
+		{name: "int", tmpl: `{{ int "3" }}`, expected: "3"},
+		{name: "required", tmpl: `{{ required "name is required" "" }}`, isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := template.New("test").Funcs(templateFuncs()).Parse(tc.tmpl)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = tmpl.Execute(&buf, tc.data)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,10 @@
+{
+  global: {
+    registry: "infra.example.com",
+    labels: {
+      tier: "infra",
+    },
+  },
+  components: {
+  },
+}
@@ -0,0 +1,15 @@
+{
+  global: {
+    labels: {
+      app: "logging",
+    },
+  },
+  components: {
+    fluentd: {
+      image: "fluentd:v1",
+      labels: {
+        component: "fluentd",
+      },
+    },
+  },
+}
@@ -0,0 +1,10 @@
+{
+  global: {
+    registry: "registry.example.com",
+    labels: {
+      team: "platform",
+    },
+  },
+  components: {
+  },
+}
@@ -0,0 +1,14 @@
+{
+  global: {
+    image: "nginx:1.13",
+  },
+  components: {
+    web: {
+      name: "web",
+      port: 8080,
+      labels: {
+        tier: "frontend",
+      },
+    },
+  },
+}
@@ -262,13 +262,13 @@ func (y *YAML) Objects(paramsStr, envName string) ([]*unstructured.Unstructured,
 
 // SetParam set parameter for a component.
 func (y *YAML) SetParam(path []string, value interface{}, options ParamOptions) error {
-	entry := fmt.Sprintf("%s-%d", y.Name(false), options.Index)
+	entry := y.entry(options.Index)
 	paramsData, err := y.readParams()
 	if err != nil {
 		return err
 	}
 
-	updatedParams, err := params.Set(path, paramsData, entry, value, paramsComponentRoot)
+	updatedParams, err := params.Set(path, paramsData, entry, value, paramsComponentRoot, params.SetWithType(options.Type))
 	if err != nil {
 		return err
 	}
@@ -280,9 +280,14 @@ func (y *YAML) SetParam(path []string, value interface{}, options ParamOptions)
 	return nil
 }
 
+// entry is the params entry for the object at index.
+func (y *YAML) entry(index int) string {
+	return fmt.Sprintf("%s-%d", y.Name(false), index)
+}
+
 // DeleteParam deletes a param.
 func (y *YAML) DeleteParam(path []string, options ParamOptions) error {
-	entry := fmt.Sprintf("%s-%d", y.Name(false), options.Index)
+	entry := y.entry(options.Index)
 	paramsData, err := y.readParams()
 	if err != nil {
 		return err
@@ -0,0 +1,173 @@
+// Package images finds and updates the container images used by an app.
+package images
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/pkg/errors"
+)
+
+// Image is a container image in a rendered object.
+type Image struct {
+	// Component is the namespaced name of the component which rendered the object.
+	Component string
+	// Object is the object's name as `kind/name`.
+	Object string
+	// Container is the name of the container.
+	Container string
+	// Image is the container's image.
+	Image string
+	// Param is the dotted path of the param which sets the image. It is
+	// empty if the image does not come from a param.
+	Param string
+}
+
+// List lists the images for containers and init containers in rendered
+// components.
+func List(rendered []pipeline.RenderedComponent) ([]Image, error) {
+	var images []Image
+
+	for _, rc := range rendered {
+		componentName := rc.Component.Name(true)
+
+		params, err := componentParams(rc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read params for %s", componentName)
+		}
+
+		for _, obj := range rc.Objects {
+			for _, c := range containers(obj.Object) {
+				images = append(images, Image{
+					Component: componentName,
+					Object:    ksutil.ObjectName(obj),
+					Container: c.name,
+					Image:     c.image,
+					Param:     findParam(params, c.image),
+				})
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// componentParams returns the params entries which belong to a rendered
+// component. YAML components have an entry per object named
+// `<component>-<index>`.
+func componentParams(rc pipeline.RenderedComponent) (map[string]interface{}, error) {
+	if rc.Params == "" {
+		return nil, nil
+	}
+
+	var doc struct {
+		Components map[string]interface{} `json:"components"`
+	}
+
+	if err := json.Unmarshal([]byte(rc.Params), &doc); err != nil {
+		return nil, err
+	}
+
+	name := rc.Component.Name(false)
+
+	m := make(map[string]interface{})
+	for k, v := range doc.Components {
+		if k == name || strings.HasPrefix(k, name+"-") {
+			m[k] = v
+		}
+	}
+
+	return m, nil
+}
+
+// findParam returns the dotted path of the first param, in path order, whose
+// value is image.
+func findParam(params map[string]interface{}, image string) string {
+	var found []string
+	walkStrings(params, nil, func(path []string, s string) {
+		if s == image {
+			found = append(found, strings.Join(path, "."))
+		}
+	})
+
+	if len(found) == 0 {
+		return ""
+	}
+
+	sort.Strings(found)
+	return found[0]
+}
+
+// walkStrings calls fn with the path of each string value in a map.
+func walkStrings(m map[string]interface{}, path []string, fn func([]string, string)) {
+	for k, v := range m {
+		cur := append(append([]string{}, path...), k)
+
+		switch t := v.(type) {
+		case string:
+			fn(cur, t)
+		case map[string]interface{}:
+			walkStrings(t, cur, fn)
+		}
+	}
+}
+
+type container struct {
+	name  string
+	image string
+}
+
+// containers finds containers and init containers anywhere in an object, so
+// pods and all workload types are covered.
+func containers(v interface{}) []container {
+	var found []container
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if k == "containers" || k == "initContainers" {
+				if items, ok := t[k].([]interface{}); ok {
+					found = append(found, containerList(items)...)
+					continue
+				}
+			}
+
+			found = append(found, containers(t[k])...)
+		}
+	case []interface{}:
+		for _, item := range t {
+			found = append(found, containers(item)...)
+		}
+	}
+
+	return found
+}
+
+func containerList(items []interface{}) []container {
+	var found []container
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		image, ok := m["image"].(string)
+		if !ok {
+			continue
+		}
+
+		name, _ := m["name"].(string)
+		found = append(found, container{name: name, image: image})
+	}
+
+	return found
+}
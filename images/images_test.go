@@ -0,0 +1,77 @@
+package images
+
+import (
+	"testing"
+
+	cmocks "github.com/bryanl/woowoo/component/mocks"
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestList(t *testing.T) {
+	web := &cmocks.Component{}
+	web.On("Name", true).Return("apps/web")
+	web.On("Name", false).Return("web")
+
+	rendered := []pipeline.RenderedComponent{
+		{
+			Component: web,
+			Params: `{"components": {
+				"web": {"image": "nginx:1.13", "proxy": {"image": "envoy:1.5"}},
+				"web-db": {"image": "postgres:10"},
+				"other": {"image": "busybox"}
+			}}`,
+			Objects: []*unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"kind":     "Deployment",
+						"metadata": map[string]interface{}{"name": "web"},
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"initContainers": []interface{}{
+										map[string]interface{}{"name": "init", "image": "busybox"},
+									},
+									"containers": []interface{}{
+										map[string]interface{}{"name": "web", "image": "nginx:1.13"},
+										map[string]interface{}{"name": "proxy", "image": "envoy:1.5"},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Object: map[string]interface{}{
+						"kind":     "Pod",
+						"metadata": map[string]interface{}{"name": "db"},
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"name": "db", "image": "postgres:10"},
+							},
+						},
+					},
+				},
+				{
+					Object: map[string]interface{}{
+						"kind":     "Service",
+						"metadata": map[string]interface{}{"name": "web"},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := List(rendered)
+	require.NoError(t, err)
+
+	expected := []Image{
+		{Component: "apps/web", Object: "deployment/web", Container: "web", Image: "nginx:1.13", Param: "web.image"},
+		{Component: "apps/web", Object: "deployment/web", Container: "proxy", Image: "envoy:1.5", Param: "web.proxy.image"},
+		{Component: "apps/web", Object: "deployment/web", Container: "init", Image: "busybox"},
+		{Component: "apps/web", Object: "pod/db", Container: "db", Image: "postgres:10", Param: "web-db.image"},
+	}
+
+	require.Equal(t, expected, got)
+}
@@ -0,0 +1,96 @@
+package images
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/pkg/errors"
+)
+
+// Repository returns the repository of an image reference without its tag or
+// digest. `registry:5000/app:1.0` has the repository `registry:5000/app`.
+func Repository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		image = image[:colon]
+	}
+
+	return image
+}
+
+// Change is a param which references an image repository.
+type Change struct {
+	// Path is the path of the param.
+	Path []string
+	// From is the current image.
+	From string
+	// To is the image with the new tag.
+	To string
+}
+
+// Retag finds string values in params which reference repo and returns the
+// changes which set their tag to tag. Values which already use the tag are
+// skipped.
+func Retag(params map[string]interface{}, repo, tag string) []Change {
+	to := repo + ":" + tag
+
+	var changes []Change
+	walkStrings(params, nil, func(path []string, s string) {
+		if s == to || Repository(s) != repo {
+			return
+		}
+
+		changes = append(changes, Change{Path: path, From: s, To: to})
+	})
+
+	sort.Slice(changes, func(i, j int) bool {
+		return strings.Join(changes[i].Path, ".") < strings.Join(changes[j].Path, ".")
+	})
+
+	return changes
+}
+
+// RetagParams sets the tag of images from repo in the components and globals
+// of a params.libsonnet. It returns the updated source and the changes made.
+func RetagParams(src, repo, tag string) (string, []Change, error) {
+	var all []Change
+
+	for _, root := range []string{"components", "global"} {
+		ok, err := params.HasRoot(src, root)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if !ok {
+			// params files are not required to have globals
+			continue
+		}
+
+		m, err := params.ToMap("", src, root)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "read %s params", root)
+		}
+
+		for _, change := range Retag(m, repo, tag) {
+			key, path := "", change.Path
+			if root == "components" {
+				key, path = path[0], path[1:]
+			}
+
+			src, err = params.Set(path, src, key, change.To, root)
+			if err != nil {
+				return "", nil, errors.Wrapf(err, "set %s", strings.Join(change.Path, "."))
+			}
+
+			change.Path = append([]string{root}, change.Path...)
+			all = append(all, change)
+		}
+	}
+
+	return src, all, nil
+}
@@ -0,0 +1,93 @@
+package images
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository(t *testing.T) {
+	cases := []struct {
+		image    string
+		expected string
+	}{
+		{image: "nginx", expected: "nginx"},
+		{image: "nginx:1.13", expected: "nginx"},
+		{image: "registry:5000/app", expected: "registry:5000/app"},
+		{image: "registry:5000/app:1.0", expected: "registry:5000/app"},
+		{image: "gcr.io/org/app@sha256:abc", expected: "gcr.io/org/app"},
+		{image: "gcr.io/org/app:1.0@sha256:abc", expected: "gcr.io/org/app"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.image, func(t *testing.T) {
+			require.Equal(t, tc.expected, Repository(tc.image))
+		})
+	}
+}
+
+func TestRetagParams(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/params.libsonnet")
+	require.NoError(t, err)
+
+	got, changes, err := RetagParams(string(b), "envoyproxy/envoy", "v1.6.0")
+	require.NoError(t, err)
+
+	expectedChanges := []Change{
+		{
+			Path: []string{"components", "web", "containers", "proxy"},
+			From: "envoyproxy/envoy@sha256:abc",
+			To:   "envoyproxy/envoy:v1.6.0",
+		},
+		{
+			Path: []string{"global", "sidecar"},
+			From: "envoyproxy/envoy:v1.5.0",
+			To:   "envoyproxy/envoy:v1.6.0",
+		},
+	}
+	require.Equal(t, expectedChanges, changes)
+
+	web, err := params.ToMap("web", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, "registry:5000/web:1.0", web["image"])
+	require.Equal(t, "envoyproxy/envoy:v1.6.0", web["containers"].(map[string]interface{})["proxy"])
+
+	global, err := params.ToMap("", got, "global")
+	require.NoError(t, err)
+	require.Equal(t, "envoyproxy/envoy:v1.6.0", global["sidecar"])
+}
+
+func TestRetagParams_no_changes(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/params.libsonnet")
+	require.NoError(t, err)
+
+	got, changes, err := RetagParams(string(b), "registry:5000/web", "1.0")
+	require.NoError(t, err)
+
+	require.Empty(t, changes)
+	require.Equal(t, string(b), got)
+}
+
+func TestRetagParams_no_globals(t *testing.T) {
+	src := `{
+  components: {
+    web: {
+      image: "registry:5000/web:0.9",
+    },
+  },
+}
+`
+
+	got, changes, err := RetagParams(src, "registry:5000/web", "1.0")
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	require.Contains(t, got, `"registry:5000/web:1.0"`)
+}
+
+func TestRetagParams_invalid(t *testing.T) {
+	_, _, err := RetagParams("{ components: ", "registry:5000/web", "1.0")
+	require.Error(t, err)
+}
@@ -0,0 +1,17 @@
+{
+  global: {
+    sidecar: "envoyproxy/envoy:v1.5.0",
+  },
+  components: {
+    web: {
+      image: "registry:5000/web:1.0",
+      containers: {
+        proxy: "envoyproxy/envoy@sha256:abc",
+      },
+      replicas: 2,
+    },
+    worker: {
+      image: "registry:5000/worker:1.0",
+    },
+  },
+}
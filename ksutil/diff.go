@@ -0,0 +1,41 @@
+package ksutil
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff creates a unified diff between two strings. It returns an empty
+// string if the strings are the same.
+func Diff(from, to, fromName, toName string) (string, error) {
+	if from == to {
+		return "", nil
+	}
+
+	ud := difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	}
+
+	return difflib.GetUnifiedDiffString(ud)
+}
+
+// splitLines splits a string into lines while keeping the line endings. A
+// missing final newline is added so the diff output stays line oriented.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+
+	lines[len(lines)-1] += "\n"
+	return lines
+}
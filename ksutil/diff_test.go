@@ -0,0 +1,43 @@
+package ksutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name     string
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name: "same",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+		},
+		{
+			name: "changed",
+			from: "a\nb\nc\n",
+			to:   "a\nB\nc\n",
+			expected: `--- from
++++ to
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Diff(tc.from, tc.to, "from", "to")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -4,25 +4,58 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"text/template"
 
+	"github.com/pkg/errors"
 	yaml "gopkg.in/yaml.v2"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 // TODO: in ksonnet... so deprecate this
 
-// Fprint prints objects to a writer in a format (yaml or json).
+const (
+	formatJSONPath   = "jsonpath"
+	formatGoTemplate = "go-template"
+)
+
+// Fprint prints objects to a writer in a format. Valid formats are `yaml`,
+// `json`, `jsonl`, `name`, `jsonpath=<expr>`, and `go-template=<tmpl>`.
+// The `json`, `jsonpath`, and `go-template` formats operate on the objects
+// wrapped in a v1 List.
 func Fprint(out io.Writer, objects []*unstructured.Unstructured, format string) error {
-	switch format {
+	name, arg := splitFormat(format)
+
+	switch name {
 	case "yaml":
 		return printYAML(out, objects)
 	case "json":
 		return printJSON(out, objects)
+	case "jsonl":
+		return printJSONL(out, objects)
+	case "name":
+		return printName(out, objects)
+	case formatJSONPath:
+		return printJSONPath(out, objects, arg)
+	case formatGoTemplate:
+		return printGoTemplate(out, objects, arg)
 	default:
 		return fmt.Errorf("unknown format: %s", format)
 	}
 }
 
+// splitFormat splits a format into its name and argument. Formats like
+// `jsonpath=<expr>` have arguments.
+func splitFormat(format string) (string, string) {
+	parts := strings.SplitN(format, "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
 func printYAML(out io.Writer, objects []*unstructured.Unstructured) error {
 	for _, obj := range objects {
 		fmt.Fprintln(out, "---")
@@ -39,15 +72,90 @@ func printYAML(out io.Writer, objects []*unstructured.Unstructured) error {
 func printJSON(out io.Writer, objects []*unstructured.Unstructured) error {
 	enc := json.NewEncoder(out)
 	enc.SetIndent("", "  ")
+
+	return enc.Encode(toList(objects))
+}
+
+func printJSONL(out io.Writer, objects []*unstructured.Unstructured) error {
+	enc := json.NewEncoder(out)
 	for _, obj := range objects {
-		// TODO: this is not valid framing for JSON
-		if len(objects) > 1 {
-			fmt.Fprintln(out, "---")
-		}
-		if err := enc.Encode(obj); err != nil {
+		if err := enc.Encode(obj.Object); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+func printName(out io.Writer, objects []*unstructured.Unstructured) error {
+	for _, obj := range objects {
+		fmt.Fprintln(out, ObjectName(obj))
+	}
+
+	return nil
+}
+
+// ObjectName returns the name of an object as `kind/name`. The kind is
+// lower case.
+func ObjectName(obj *unstructured.Unstructured) string {
+	name := obj.GetName()
+	if name == "" {
+		name = obj.GetGenerateName()
+	}
+
+	return fmt.Sprintf("%s/%s", strings.ToLower(obj.GetKind()), name)
+}
+
+func printJSONPath(out io.Writer, objects []*unstructured.Unstructured, expr string) error {
+	if expr == "" {
+		return errors.New("jsonpath format requires an expression")
+	}
+
+	// allow `.items[*].metadata.name` as a shortcut for `{.items[*].metadata.name}`
+	if !strings.Contains(expr, "{") {
+		expr = fmt.Sprintf("{%s}", expr)
+	}
+
+	jp := jsonpath.New("out")
+	if err := jp.Parse(expr); err != nil {
+		return errors.Wrap(err, "parse jsonpath")
+	}
+
+	if err := jp.Execute(out, toList(objects)); err != nil {
+		return errors.Wrap(err, "execute jsonpath")
+	}
+
+	fmt.Fprintln(out)
+	return nil
+}
+
+func printGoTemplate(out io.Writer, objects []*unstructured.Unstructured, tmpl string) error {
+	if tmpl == "" {
+		return errors.New("go-template format requires a template")
+	}
+
+	t, err := template.New("out").Parse(tmpl)
+	if err != nil {
+		return errors.Wrap(err, "parse template")
+	}
+
+	if err := t.Execute(out, toList(objects)); err != nil {
+		return errors.Wrap(err, "execute template")
+	}
+
+	return nil
+}
+
+// toList wraps objects in a v1 List.
+func toList(objects []*unstructured.Unstructured) map[string]interface{} {
+	items := make([]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, obj.Object)
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	}
+}
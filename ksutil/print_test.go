@@ -0,0 +1,136 @@
+package ksutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFprint(t *testing.T) {
+	objects := []*unstructured.Unstructured{
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]interface{}{
+					"name": "a",
+				},
+			},
+		},
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "b",
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		format   string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:   "yaml",
+			format: "yaml",
+			expected: `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+`,
+		},
+		{
+			name:   "json",
+			format: "json",
+			expected: `{
+  "apiVersion": "v1",
+  "items": [
+    {
+      "apiVersion": "v1",
+      "kind": "Service",
+      "metadata": {
+        "name": "a"
+      }
+    },
+    {
+      "apiVersion": "apps/v1",
+      "kind": "Deployment",
+      "metadata": {
+        "name": "b"
+      }
+    }
+  ],
+  "kind": "List"
+}
+`,
+		},
+		{
+			name:   "jsonl",
+			format: "jsonl",
+			expected: `{"apiVersion":"v1","kind":"Service","metadata":{"name":"a"}}
+{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"b"}}
+`,
+		},
+		{
+			name:     "name",
+			format:   "name",
+			expected: "service/a\ndeployment/b\n",
+		},
+		{
+			name:     "jsonpath",
+			format:   "jsonpath={.items[*].metadata.name}",
+			expected: "a b\n",
+		},
+		{
+			name:     "jsonpath without braces",
+			format:   "jsonpath=.items[0].kind",
+			expected: "Service\n",
+		},
+		{
+			name:   "jsonpath missing expression",
+			format: "jsonpath=",
+			isErr:  true,
+		},
+		{
+			name:     "go-template",
+			format:   `go-template={{range .items}}{{.metadata.name}}{{"\n"}}{{end}}`,
+			expected: "a\nb\n",
+		},
+		{
+			name:   "invalid go-template",
+			format: "go-template={{.items",
+			isErr:  true,
+		},
+		{
+			name:   "unknown",
+			format: "xml",
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Fprint(&buf, objects, tc.format)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+)
+
+// Computed is a param whose value is computed by a Jsonnet expression, e.g.
+// `registry + "/web"` or `$.global.replicas`, rather than being a literal.
+type Computed struct {
+	// Expression is the source of the expression.
+	Expression string
+	// Value is the evaluated value. It is nil if the expression could not be
+	// evaluated on its own.
+	Value interface{}
+}
+
+// MarshalJSON marshals the evaluated value.
+func (c Computed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Value)
+}
+
+// computedError is returned when a write would change a computed value.
+type computedError struct {
+	path       string
+	expression string
+}
+
+func (e *computedError) Error() string {
+	return fmt.Sprintf("param %s is computed by `%s`; edit the params file to change it",
+		e.path, e.expression)
+}
+
+// evaluate evaluates the value at path in the params file. Only the fields
+// needed for the value are evaluated, so a value which can't be evaluated
+// doesn't affect the others.
+func (pf *paramsFile) evaluate(path []string) (interface{}, error) {
+	var index string
+	for _, k := range path {
+		index += "[" + strconv.Quote(k) + "]"
+	}
+
+	vm := jsonnet.MakeVM()
+	for k, v := range pf.extCode {
+		vm.ExtCode(k, v)
+	}
+
+	snippet := fmt.Sprintf("(\n%s\n)%s", pf.src, index)
+	out, err := vm.EvaluateSnippet("params.libsonnet", snippet)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(out), &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// computed creates a computed value for the node at path.
+func (pf *paramsFile) computed(node ast.Node, path []string) Computed {
+	c := Computed{Expression: pf.source(node)}
+	if v, err := pf.evaluate(path); err == nil {
+		c.Value = v
+	}
+
+	return c
+}
+
+// source returns the source of a node.
+func (pf *paramsFile) source(node ast.Node) string {
+	loc := node.Loc()
+	if loc == nil || loc.Begin.Line == 0 {
+		return fmt.Sprintf("<%T>", node)
+	}
+
+	return pf.src[pf.begin(node):pf.end(node)]
+}
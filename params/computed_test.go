@@ -0,0 +1,92 @@
+package params
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToMap_computed(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/computed-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := ToMap("web", string(b), "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"image": Computed{
+			Expression: `registry + "/web:" + tag`,
+			Value:      "registry.example.com/web:1.0",
+		},
+		"name": Computed{
+			Expression: `std.join("-", ["web", "app"])`,
+			Value:      "web-app",
+		},
+		"offset": float64(-1),
+		"replicas": Computed{
+			Expression: "$.global.replicas",
+			Value:      float64(2),
+		},
+		"team": "core",
+	}
+
+	require.Equal(t, expected, got)
+}
+
+func TestSet_computed(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/computed-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := Set([]string{"team"}, string(b), "web", "platform", "components")
+	require.NoError(t, err)
+
+	m, err := ToMap("web", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, "platform", m["team"])
+	require.Equal(t, "registry.example.com/web:1.0", m["image"].(Computed).Value)
+
+	_, err = Set([]string{"replicas"}, string(b), "web", 3, "components")
+	require.EqualError(t, err, "param replicas is computed by `$.global.replicas`; edit the params file to change it")
+
+	_, err = Set([]string{"image", "tag"}, string(b), "web", "2.0", "components")
+	require.Error(t, err)
+}
+
+func TestDelete_computed(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/computed-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := Delete([]string{"team"}, string(b), "web", "components")
+	require.NoError(t, err)
+
+	m, err := ToMap("web", got, "components")
+	require.NoError(t, err)
+	require.NotContains(t, m, "team")
+
+	_, err = Delete([]string{"name"}, string(b), "web", "components")
+	require.Error(t, err)
+}
+
+func TestEnvToMap_computed(t *testing.T) {
+	src := `local params = std.extVar("__ksonnet/params");
+
+params + {
+  components +: {
+    web +: {
+      replicas: params.global.replicas,
+      team: "ops" + "-" + "core",
+    },
+  },
+}`
+
+	got, err := EnvToMap("web", src, "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"replicas": Computed{Expression: "params.global.replicas"},
+		"team":     Computed{Expression: `"ops" + "-" + "core"`, Value: "ops-core"},
+	}
+
+	require.Equal(t, expected, got)
+}
@@ -0,0 +1,117 @@
+package params
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DiffStatus describes how a param differs between two sets of params.
+type DiffStatus string
+
+const (
+	// DiffEqual is a param with the same value on both sides.
+	DiffEqual DiffStatus = "equal"
+	// DiffChanged is a param with different values on each side.
+	DiffChanged DiffStatus = "changed"
+	// DiffLeftOnly is a param which only exists on the left side.
+	DiffLeftOnly DiffStatus = "left only"
+	// DiffRightOnly is a param which only exists on the right side.
+	DiffRightOnly DiffStatus = "right only"
+)
+
+// Difference is a param compared between two sets of params. Nested keys
+// are joined with dots in Key, and kept as segments in Path.
+type Difference struct {
+	Component string
+	Key       string
+	Path      []string
+	Left      interface{}
+	Right     interface{}
+	Status    DiffStatus
+}
+
+// Diff compares two sets of component params. Differences are sorted by
+// component and key.
+func Diff(left, right map[string]map[string]interface{}) []Difference {
+	components := make(map[string]bool)
+	for name := range left {
+		components[name] = true
+	}
+	for name := range right {
+		components[name] = true
+	}
+
+	var differences []Difference
+	for name := range components {
+		l := flatten(left[name], nil)
+		r := flatten(right[name], nil)
+
+		keys := make(map[string]bool)
+		for k := range l {
+			keys[k] = true
+		}
+		for k := range r {
+			keys[k] = true
+		}
+
+		for k := range keys {
+			lp, inLeft := l[k]
+			rp, inRight := r[k]
+			lv, rv := lp.value, rp.value
+
+			path := lp.path
+			if !inLeft {
+				path = rp.path
+			}
+
+			d := Difference{Component: name, Key: k, Path: path, Left: lv, Right: rv}
+			switch {
+			case !inRight:
+				d.Status = DiffLeftOnly
+			case !inLeft:
+				d.Status = DiffRightOnly
+			case reflect.DeepEqual(lv, rv):
+				d.Status = DiffEqual
+			default:
+				d.Status = DiffChanged
+			}
+
+			differences = append(differences, d)
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		if differences[i].Component != differences[j].Component {
+			return differences[i].Component < differences[j].Component
+		}
+		return differences[i].Key < differences[j].Key
+	})
+
+	return differences
+}
+
+// flatParam is a param from nested maps.
+type flatParam struct {
+	path  []string
+	value interface{}
+}
+
+// flatten converts nested maps to params keyed by their paths joined with
+// dots.
+func flatten(m map[string]interface{}, path []string) map[string]flatParam {
+	out := make(map[string]flatParam)
+	for k, v := range m {
+		childPath := append(append([]string{}, path...), k)
+		if child, ok := v.(map[string]interface{}); ok && len(child) > 0 {
+			for ck, cv := range flatten(child, childPath) {
+				out[ck] = cv
+			}
+			continue
+		}
+
+		out[strings.Join(childPath, ".")] = flatParam{path: childPath, value: v}
+	}
+
+	return out
+}
@@ -0,0 +1,75 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	left := map[string]map[string]interface{}{
+		"web": {
+			"replicas": float64(1),
+			"image":    "web:1",
+			"labels": map[string]interface{}{
+				"env":  "staging",
+				"team": "web",
+			},
+		},
+		"worker": {
+			"replicas": float64(1),
+		},
+	}
+
+	right := map[string]map[string]interface{}{
+		"web": {
+			"replicas": float64(3),
+			"image":    "web:1",
+			"labels": map[string]interface{}{
+				"env":  "production",
+				"team": "web",
+			},
+			"debug": false,
+		},
+	}
+
+	got := Diff(left, right)
+
+	expected := []Difference{
+		{Component: "web", Key: "debug", Path: []string{"debug"}, Right: false, Status: DiffRightOnly},
+		{Component: "web", Key: "image", Path: []string{"image"}, Left: "web:1", Right: "web:1", Status: DiffEqual},
+		{Component: "web", Key: "labels.env", Path: []string{"labels", "env"}, Left: "staging", Right: "production", Status: DiffChanged},
+		{Component: "web", Key: "labels.team", Path: []string{"labels", "team"}, Left: "web", Right: "web", Status: DiffEqual},
+		{Component: "web", Key: "replicas", Path: []string{"replicas"}, Left: float64(1), Right: float64(3), Status: DiffChanged},
+		{Component: "worker", Key: "replicas", Path: []string{"replicas"}, Left: float64(1), Status: DiffLeftOnly},
+	}
+
+	require.Equal(t, expected, got)
+}
+
+func TestDiff_dotted_keys(t *testing.T) {
+	left := map[string]map[string]interface{}{
+		"web": {},
+	}
+
+	right := map[string]map[string]interface{}{
+		"web": {
+			"annotations": map[string]interface{}{
+				"prometheus.io/scrape": "true",
+			},
+		},
+	}
+
+	got := Diff(left, right)
+
+	expected := []Difference{
+		{
+			Component: "web",
+			Key:       "annotations.prometheus.io/scrape",
+			Path:      []string{"annotations", "prometheus.io/scrape"},
+			Right:     "true",
+			Status:    DiffRightOnly,
+		},
+	}
+	require.Equal(t, expected, got)
+}
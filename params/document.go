@@ -0,0 +1,45 @@
+package params
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Document is a namespace's params in a form other tools can read and write
+// without understanding Jsonnet. It is encoded as YAML or JSON.
+type Document struct {
+	// Namespace is the component namespace the params belong to.
+	Namespace string `json:"namespace"`
+	// Environment is set if the params are an environment's effective params.
+	Environment string `json:"environment,omitempty"`
+	// Global are the global params.
+	Global map[string]interface{} `json:"global,omitempty"`
+	// Components are the params for each component entry.
+	Components map[string]map[string]interface{} `json:"components,omitempty"`
+}
+
+// Validate checks if changes can be merged into params without changing the
+// type of an existing value.
+func Validate(current, changes map[string]interface{}) error {
+	cp, err := copyValues(current)
+	if err != nil {
+		return err
+	}
+
+	return mergeMaps(cp, changes, nil)
+}
+
+func copyValues(m map[string]interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "copy params")
+	}
+
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, errors.Wrap(err, "copy params")
+	}
+
+	return out, nil
+}
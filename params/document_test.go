@@ -0,0 +1,29 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	current := map[string]interface{}{
+		"replicas": float64(1),
+		"labels": map[string]interface{}{
+			"app": "web",
+		},
+	}
+
+	changes := map[string]interface{}{
+		"replicas": float64(3),
+		"labels": map[string]interface{}{
+			"tier": "frontend",
+		},
+	}
+
+	require.NoError(t, Validate(current, changes))
+
+	// current is not modified
+	require.Equal(t, float64(1), current["replicas"])
+	require.Len(t, current["labels"], 1)
+}
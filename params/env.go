@@ -0,0 +1,168 @@
+package params
+
+import (
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+// envParamsExtCode is the external code used to evaluate computed overrides.
+// The component params aren't available, so overrides which use them can't be
+// evaluated.
+var envParamsExtCode = map[string]string{
+	"__ksonnet/params": "{}",
+}
+
+// parseEnvParams parses environment params. Environment params extend the
+// component params with overrides, e.g. `params + { components +: {} }`.
+// Objects in the overrides are written with `+:` so they extend the component
+// params rather than replace them.
+func parseEnvParams(src string) (*paramsFile, error) {
+	node, err := parseNode(src)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := findOverrides(node)
+	if err != nil {
+		return nil, err
+	}
+
+	pf := newParamsFile(src, overrides)
+	pf.extCode = envParamsExtCode
+	pf.extend = true
+
+	return pf, nil
+}
+
+// findOverrides finds the object which holds the component overrides.
+func findOverrides(node ast.Node) (*astext.Object, error) {
+	switch t := node.(type) {
+	case *ast.Local:
+		for _, bind := range t.Binds {
+			if obj, err := findOverrides(bind.Body); err == nil {
+				return obj, nil
+			}
+		}
+
+		return findOverrides(t.Body)
+	case *ast.Binary:
+		return findOverrides(t.Right)
+	case *astext.Object:
+		for i := range t.Fields {
+			id, err := jsonnetutil.FieldID(t.Fields[i])
+			if err != nil {
+				continue
+			}
+
+			if _, ok := t.Fields[i].Expr2.(*astext.Object); ok && id == "components" {
+				return t, nil
+			}
+		}
+	}
+
+	return nil, errors.New("could not find component overrides in environment params")
+}
+
+// EnvToMap converts a component's overrides in environment params to a map.
+func EnvToMap(componentName, src, root string) (map[string]interface{}, error) {
+	pf, err := parseEnvParams(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return pf.toMap(componentName, root)
+}
+
+// EnvHasRoot reports if environment params have a root object, e.g. `global`.
+func EnvHasRoot(src, root string) (bool, error) {
+	pf, err := parseEnvParams(src)
+	if err != nil {
+		return false, err
+	}
+
+	return pf.hasRoot(root)
+}
+
+// EnvSet sets a param override in environment params.
+func EnvSet(path []string, paramsData, key string, value interface{}, root string, opts ...SetOpt) (string, error) {
+	pf, err := parseEnvParams(paramsData)
+	if err != nil {
+		return "", err
+	}
+
+	return set(pf, path, key, value, root, opts...)
+}
+
+// EnvDelete deletes a param override from environment params.
+func EnvDelete(path []string, paramsData, key, root string) (string, error) {
+	pf, err := parseEnvParams(paramsData)
+	if err != nil {
+		return "", err
+	}
+
+	return deleteParam(pf, path, key, root)
+}
+
+func findField(obj *astext.Object, id string) (*astext.ObjectField, error) {
+	for i := range obj.Fields {
+		if obj.Fields[i].Kind == ast.ObjectLocal {
+			continue
+		}
+
+		fieldID, err := jsonnetutil.FieldID(obj.Fields[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if fieldID == id {
+			return &obj.Fields[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("unable to find field %q", id)
+}
+
+// extendFields marks the object fields in obj as `+:`.
+func extendFields(obj *astext.Object) {
+	for i := range obj.Fields {
+		child, ok := obj.Fields[i].Expr2.(*astext.Object)
+		if !ok {
+			continue
+		}
+
+		obj.Fields[i].SuperSugar = true
+		extendFields(child)
+	}
+}
+
+// quoteExtendedFields works around the printer dropping `+:` from fields with
+// string ids, e.g. `"guestbook-ui" +:`. The fields are converted to
+// identifier fields whose ids include the quotes.
+func quoteExtendedFields(node ast.Node) {
+	switch t := node.(type) {
+	case *ast.Local:
+		for _, bind := range t.Binds {
+			quoteExtendedFields(bind.Body)
+		}
+		quoteExtendedFields(t.Body)
+	case *ast.Binary:
+		quoteExtendedFields(t.Left)
+		quoteExtendedFields(t.Right)
+	case *astext.Object:
+		for i := range t.Fields {
+			field := &t.Fields[i]
+			if field.Kind == ast.ObjectFieldStr && field.SuperSugar {
+				if ls, ok := field.Expr1.(*ast.LiteralString); ok {
+					id := ast.Identifier(`"` + ls.Value + `"`)
+					field.Kind = ast.ObjectFieldID
+					field.Id = &id
+					field.Expr1 = nil
+				}
+			}
+
+			quoteExtendedFields(field.Expr2)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package params
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvToMap(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := EnvToMap("guestbook-ui", string(b), "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"replicas": float64(3),
+	}
+
+	require.Equal(t, expected, got)
+}
+
+func TestEnvSet(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := EnvSet([]string{"metadata", "labels", "env"}, string(b), "guestbook-ui", "prod", "components")
+	require.NoError(t, err)
+
+	got, err = EnvSet([]string{"replicas"}, got, "redis", 1, "components")
+	require.NoError(t, err)
+
+	got, err = EnvSet([]string{"registry"}, got, "", "prod.example.com", "global")
+	require.NoError(t, err)
+
+	expected, err := ioutil.ReadFile("testdata/env-params-set.libsonnet")
+	require.NoError(t, err)
+
+	require.Equal(t, string(expected), got)
+}
+
+func TestEnvDelete(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params.libsonnet")
+	require.NoError(t, err)
+
+	got, err := EnvDelete([]string{"replicas"}, string(b), "guestbook-ui", "components")
+	require.NoError(t, err)
+
+	expected, err := ioutil.ReadFile("testdata/env-params-delete.libsonnet")
+	require.NoError(t, err)
+
+	require.Equal(t, string(expected), got)
+}
+
+func TestEnvSet_no_overrides(t *testing.T) {
+	_, err := EnvSet([]string{"replicas"}, `std.extVar("__ksonnet/params")`, "redis", 1, "components")
+	require.Error(t, err)
+}
+
+func TestEnvHasRoot(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/env-params-set.libsonnet")
+	require.NoError(t, err)
+
+	ok, err := EnvHasRoot(string(b), "global")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	b, err = ioutil.ReadFile("testdata/env-params-staging.libsonnet")
+	require.NoError(t, err)
+
+	ok, err = EnvHasRoot(string(b), "global")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,511 @@
+package params
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+	"github.com/ksonnet/ksonnet/pkg/docparser"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+// paramsFile is a parsed params file. Params are read from and written to
+// obj. Changes are written back to the source by editing only the text of the
+// changed fields, so comments and formatting elsewhere are kept.
+type paramsFile struct {
+	src string
+	obj *astext.Object
+	// extCode is the external code available when evaluating computed values.
+	extCode map[string]string
+	// extend writes objects with `+:`.
+	extend bool
+
+	// lineOffsets are the offsets of the lines in src.
+	lineOffsets []int
+	// fields and elements are the parsed fields of objects and elements of
+	// lists. Changes are found by comparing them to the current AST.
+	fields   map[*astext.Object][]astext.ObjectField
+	elements map[*ast.Array][]ast.Node
+}
+
+func parseParams(src string) (*paramsFile, error) {
+	node, err := parseNode(src)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := rootObject(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return newParamsFile(src, obj), nil
+}
+
+func parseNode(src string) (ast.Node, error) {
+	tokens, err := docparser.Lex("params.libsonnet", src)
+	if err != nil {
+		return nil, errors.Wrap(err, "lex jsonnet")
+	}
+
+	node, err := docparser.Parse(tokens)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse jsonnet")
+	}
+
+	return node, nil
+}
+
+// rootObject finds the object a params file evaluates to. Params files can
+// declare locals before the object.
+func rootObject(node ast.Node) (*astext.Object, error) {
+	switch t := node.(type) {
+	case *ast.Local:
+		return rootObject(t.Body)
+	case *astext.Object:
+		return t, nil
+	default:
+		return nil, errors.New("root was not an object")
+	}
+}
+
+func (pf *paramsFile) toMap(componentName, root string) (map[string]interface{}, error) {
+	path := make([]string, 0)
+	if root != "" {
+		path = append(path, root)
+	}
+
+	if componentName != "" {
+		path = append(path, componentName)
+	}
+
+	child, err := jsonnetutil.FindObject(pf.obj, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find child paths for %s", strings.Join(path, "."))
+	}
+
+	m, err := pf.findValues(child, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	name := componentName
+	if name == "" {
+		name = root
+	}
+
+	paramsMap, ok := m[name].(map[string]interface{})
+	if !ok {
+		if c, isComputed := m[name].(Computed); isComputed {
+			return nil, errors.Errorf("params for %q are computed by `%s`", name, c.Expression)
+		}
+
+		return nil, errors.Errorf("could not find %q in components", componentName)
+	}
+
+	return paramsMap, nil
+}
+
+// hasRoot reports if the params object has a field named root.
+func (pf *paramsFile) hasRoot(root string) (bool, error) {
+	for _, field := range pf.obj.Fields {
+		id, err := jsonnetutil.FieldID(field)
+		if err != nil {
+			return false, err
+		}
+
+		if id == root {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// findValues converts an object at path to a map. Values which aren't
+// literals are evaluated.
+func (pf *paramsFile) findValues(obj *astext.Object, path []string) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+
+	for i := range obj.Fields {
+		if obj.Fields[i].Kind == ast.ObjectLocal {
+			continue
+		}
+
+		id, err := jsonnetutil.FieldID(obj.Fields[i])
+		if err != nil {
+			return nil, err
+		}
+
+		childPath := append(append([]string{}, path...), id)
+
+		switch t := obj.Fields[i].Expr2.(type) {
+		case *astext.Object:
+			child, err := pf.findValues(t, childPath)
+			if err != nil {
+				return nil, err
+			}
+
+			m[id] = child
+		default:
+			if v, err := literalValue(t); err == nil {
+				m[id] = v
+				continue
+			}
+
+			m[id] = pf.computed(t, childPath)
+		}
+	}
+
+	return m, nil
+}
+
+// computedNode returns the computed node at or above steps. It returns nil
+// if every value on the path is a literal, an object or a list.
+func (pf *paramsFile) computedNode(steps []step) ast.Node {
+	var cur ast.Node = pf.obj
+	for _, s := range steps {
+		switch t := cur.(type) {
+		case *astext.Object:
+			field, err := findField(t, s.key)
+			if s.kind != stepKey || err != nil {
+				return nil
+			}
+
+			cur = field.Expr2
+		case *ast.Array:
+			n, err := selectElement(t, s)
+			if err != nil {
+				return nil
+			}
+
+			cur = t.Elements[n]
+		default:
+			return computedOrNil(cur)
+		}
+	}
+
+	if _, ok := cur.(*astext.Object); ok {
+		return nil
+	}
+
+	return computedOrNil(cur)
+}
+
+func computedOrNil(node ast.Node) ast.Node {
+	if _, err := literalValue(node); err == nil {
+		return nil
+	}
+
+	return node
+}
+
+// checkLiteral returns an error if setting value at path would change a
+// computed value. base is the path of the params in the file.
+func (pf *paramsFile) checkLiteral(base, path []string, value interface{}) error {
+	steps, err := parsePath(append(append([]string{}, base...), path...))
+	if err != nil {
+		return err
+	}
+
+	appending := steps[len(steps)-1].kind == stepAppend
+	if appending {
+		steps = steps[:len(steps)-1]
+	}
+
+	if node := pf.computedNode(steps); node != nil {
+		return &computedError{path: strings.Join(path, "."), expression: pf.source(node)}
+	}
+
+	if m, ok := value.(map[string]interface{}); ok && !appending {
+		for k, v := range m {
+			childPath := append(append([]string{}, path...), k)
+			if err := pf.checkLiteral(base, childPath, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// set sets value at path. Objects are merged into existing objects a key at a
+// time, so values which aren't being set, including computed ones, are left
+// as they are. List elements must exist unless they are appended.
+func (pf *paramsFile) set(path []string, value interface{}) error {
+	steps, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	i := lastSelector(steps)
+	if i < 0 {
+		k, _ := stepKeys(steps)
+		return setKeys(pf.obj, k, value, pf.extend)
+	}
+
+	tail, _ := stepKeys(steps[i+1:])
+
+	node, err := walk(pf.obj, steps[:i])
+	if err != nil {
+		if k, ok := stepKeys(steps[:i]); ok && steps[i].kind == stepAppend {
+			// The list doesn't exist yet.
+			return setKeys(pf.obj, k, []interface{}{value}, pf.extend)
+		}
+
+		return err
+	}
+
+	array, ok := node.(*ast.Array)
+	if !ok {
+		return errors.Errorf("%s is not a list", pathString(steps[:i]))
+	}
+
+	if steps[i].kind == stepAppend {
+		for j := len(tail) - 1; j >= 0; j-- {
+			value = map[string]interface{}{tail[j]: value}
+		}
+
+		element, err := valueNode(value)
+		if err != nil {
+			return err
+		}
+
+		array.Elements = append(array.Elements, element)
+		return nil
+	}
+
+	n, err := selectElement(array, steps[i])
+	if err != nil {
+		return errors.Wrap(err, pathString(steps[:i]))
+	}
+
+	if obj, ok := array.Elements[n].(*astext.Object); ok {
+		if m, isMap := value.(map[string]interface{}); (isMap && len(m) > 0) || len(tail) > 0 {
+			return setKeys(obj, tail, value, false)
+		}
+	}
+
+	if len(tail) > 0 {
+		return errors.Errorf("%s is not an object", pathString(steps[:i+1]))
+	}
+
+	element, err := valueNode(value)
+	if err != nil {
+		return err
+	}
+
+	array.Elements[n] = element
+	return nil
+}
+
+// setKeys sets value at a path of keys in obj. Objects are written with `+:`
+// if extend is set.
+func setKeys(obj *astext.Object, path []string, value interface{}, extend bool) error {
+	if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+		if child := objectAt(obj, path); child != nil {
+			var names []string
+			for k := range m {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+
+			for _, k := range names {
+				childPath := append(append([]string{}, path...), k)
+				if err := setKeys(obj, childPath, m[k], extend); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	}
+
+	// Missing objects on the path are written with the value.
+	for i := len(path) - 1; i > 0 && objectAt(obj, path[:i]) == nil; i-- {
+		value = map[string]interface{}{path[i]: value}
+		path = path[:i]
+	}
+
+	node, err := valueNode(value)
+	if err != nil {
+		return err
+	}
+
+	if child, ok := node.(*astext.Object); ok && extend {
+		extendFields(child)
+	}
+
+	// Fields are added here since jsonnetutil.Set can't create fields which
+	// need quotes, e.g. `"prometheus.io/scrape"`.
+	if parent := objectAt(obj, path[:len(path)-1]); parent != nil {
+		if _, err := findField(parent, path[len(path)-1]); err != nil {
+			parent.Fields = append(parent.Fields, *newField(path[len(path)-1]))
+		}
+	}
+
+	if err := jsonnetutil.Set(obj, path, node); err != nil {
+		return errors.Wrap(err, "update params")
+	}
+
+	if extend {
+		cur := obj
+		for _, k := range path {
+			field, err := findField(cur, k)
+			if err != nil {
+				return err
+			}
+
+			child, ok := field.Expr2.(*astext.Object)
+			if !ok {
+				break
+			}
+
+			field.SuperSugar = true
+			cur = child
+		}
+	}
+
+	return nil
+}
+
+// delete deletes the value at path. Deleting a list element removes it from
+// the list.
+func (pf *paramsFile) delete(path []string) error {
+	steps, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	last := steps[len(steps)-1]
+	parent, err := walk(pf.obj, steps[:len(steps)-1])
+	if err != nil {
+		return err
+	}
+
+	switch t := parent.(type) {
+	case *astext.Object:
+		if last.kind != stepKey {
+			return errors.Errorf("%s is not a list", pathString(steps[:len(steps)-1]))
+		}
+
+		for i := range t.Fields {
+			if t.Fields[i].Kind == ast.ObjectLocal {
+				continue
+			}
+
+			fieldID, err := jsonnetutil.FieldID(t.Fields[i])
+			if err != nil {
+				return err
+			}
+
+			if fieldID == last.key {
+				t.Fields = append(t.Fields[:i], t.Fields[i+1:]...)
+				return nil
+			}
+		}
+
+		return nil
+	case *ast.Array:
+		if last.kind == stepKey || last.kind == stepAppend {
+			return errors.Errorf("can't delete %s", pathString(steps))
+		}
+
+		n, err := selectElement(t, last)
+		if err != nil {
+			return errors.Wrap(err, pathString(steps[:len(steps)-1]))
+		}
+
+		t.Elements = append(t.Elements[:n], t.Elements[n+1:]...)
+		return nil
+	default:
+		return errors.Errorf("%s is not an object or a list", pathString(steps[:len(steps)-1]))
+	}
+}
+
+// objectAt returns the object at a path of keys in obj, or nil if there isn't
+// one.
+func objectAt(obj *astext.Object, path []string) *astext.Object {
+	cur := obj
+	for _, k := range path {
+		field, err := findField(cur, k)
+		if err != nil {
+			return nil
+		}
+
+		child, ok := field.Expr2.(*astext.Object)
+		if !ok {
+			return nil
+		}
+
+		cur = child
+	}
+
+	return cur
+}
+
+// newField creates a field. Names which aren't identifiers are quoted.
+func newField(name string) *astext.ObjectField {
+	field, err := astext.CreateField(name)
+	if err != nil {
+		field = &astext.ObjectField{ObjectField: ast.ObjectField{
+			Kind:  ast.ObjectFieldStr,
+			Expr1: &ast.LiteralString{Value: name, Kind: ast.StringDouble},
+		}}
+	}
+
+	field.Hide = ast.ObjectFieldInherit
+	return field
+}
+
+// valueNode converts a value to a node.
+func valueNode(value interface{}) (ast.Node, error) {
+	switch t := value.(type) {
+	case Computed:
+		return nil, errors.Errorf("can't write value computed by `%s`", t.Expression)
+	case nil:
+		// The printer doesn't print null literals.
+		return &ast.Var{Id: "null"}, nil
+	case map[string]interface{}:
+		var names []string
+		for k := range t {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		obj := &astext.Object{}
+		for _, k := range names {
+			field := newField(k)
+			var err error
+			if field.Expr2, err = valueNode(t[k]); err != nil {
+				return nil, err
+			}
+
+			obj.Fields = append(obj.Fields, *field)
+		}
+
+		return obj, nil
+	case []interface{}:
+		array := &ast.Array{}
+		for i := range t {
+			element, err := valueNode(t[i])
+			if err != nil {
+				return nil, err
+			}
+
+			array.Elements = append(array.Elements, element)
+		}
+
+		return array, nil
+	}
+
+	obj, err := nm.KVFromMap(map[string]interface{}{"value": value})
+	if err != nil {
+		return nil, errors.Wrap(err, "convert param to node")
+	}
+
+	return obj.Node().(*astext.Object).Fields[0].Expr2, nil
+}
@@ -0,0 +1,152 @@
+package params
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readListParams(t *testing.T) string {
+	b, err := ioutil.ReadFile("testdata/list-params.libsonnet")
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestToMap_lists(t *testing.T) {
+	got, err := ToMap("web", readListParams(t), "components")
+	require.NoError(t, err)
+
+	expected := []interface{}{
+		map[string]interface{}{"name": "http", "port": float64(80)},
+		map[string]interface{}{"name": "metrics", "port": float64(9090)},
+	}
+
+	require.Equal(t, expected, got["ports"])
+	require.Equal(t, []interface{}{"--verbose"}, got["args"])
+}
+
+func TestSet_lists(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		value    interface{}
+		key      string
+		expected interface{}
+	}{
+		{
+			name:     "index",
+			path:     "containers[1].name",
+			value:    "proxy",
+			key:      "containers",
+			expected: "proxy",
+		},
+		{
+			name:     "select by key",
+			path:     "ports[name=http].port",
+			value:    8080,
+			key:      "ports",
+			expected: float64(8080),
+		},
+		{
+			name:  "append",
+			path:  "ports[+]",
+			value: map[string]interface{}{"name": "https", "port": 443},
+			key:   "ports",
+		},
+		{
+			name:  "append to a new list",
+			path:  "volumes[+]",
+			value: map[string]interface{}{"name": "data"},
+			key:   "volumes",
+			expected: []interface{}{
+				map[string]interface{}{"name": "data"},
+			},
+		},
+		{
+			name:     "nested list",
+			path:     "containers[name=web].env[name=MODE].value",
+			value:    "dev",
+			key:      "containers",
+			expected: "dev",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Set(SplitPath(tc.path), readListParams(t), "web", tc.value, "components")
+			require.NoError(t, err)
+
+			m, err := ToMap("web", got, "components")
+			require.NoError(t, err)
+
+			switch tc.name {
+			case "index":
+				require.Equal(t, tc.expected, m["containers"].([]interface{})[1].(map[string]interface{})["name"])
+			case "select by key":
+				require.Equal(t, tc.expected, m["ports"].([]interface{})[0].(map[string]interface{})["port"])
+			case "append":
+				ports := m["ports"].([]interface{})
+				require.Len(t, ports, 3)
+				require.Equal(t, map[string]interface{}{"name": "https", "port": float64(443)}, ports[2])
+			case "append to a new list":
+				require.Equal(t, tc.expected, m[tc.key])
+			case "nested list":
+				container := m["containers"].([]interface{})[0].(map[string]interface{})
+				env := container["env"].([]interface{})[0].(map[string]interface{})
+				require.Equal(t, tc.expected, env["value"])
+			}
+		})
+	}
+}
+
+func TestSet_lists_errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		path  string
+		value interface{}
+	}{
+		{name: "out of range", path: "containers[2].name", value: "x"},
+		{name: "no match", path: "ports[name=grpc].port", value: 1},
+		{name: "type conflict", path: "ports[0].port", value: "http"},
+		{name: "key of a list", path: "ports.port", value: 1},
+		{name: "index of an object", path: "containers[0][0]", value: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Set(SplitPath(tc.path), readListParams(t), "web", tc.value, "components")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDelete_lists(t *testing.T) {
+	got, err := Delete(SplitPath("ports[name=http]"), readListParams(t), "web", "components")
+	require.NoError(t, err)
+
+	got, err = Delete(SplitPath("containers[0].env[0].value"), got, "web", "components")
+	require.NoError(t, err)
+
+	m, err := ToMap("web", got, "components")
+	require.NoError(t, err)
+
+	expected := []interface{}{
+		map[string]interface{}{"name": "metrics", "port": float64(9090)},
+	}
+	require.Equal(t, expected, m["ports"])
+
+	container := m["containers"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, []interface{}{map[string]interface{}{"name": "MODE"}}, container["env"])
+
+	_, err = Delete(SplitPath("ports[+]"), readListParams(t), "web", "components")
+	require.Error(t, err)
+}
+
+func TestSet_lists_keeps_source(t *testing.T) {
+	got, err := Set([]string{"replicas"}, readListParams(t), "web", 2, "components")
+	require.NoError(t, err)
+
+	require.Contains(t, got, `{ name: "http", port: 80 },`)
+}
@@ -1,153 +1,240 @@
 package params
 
 import (
-	"bytes"
 	"encoding/json"
-	"fmt"
-	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/google/go-jsonnet/ast"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
-	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
-	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
 	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
 )
 
-// Set sets a param value.
-func Set(path []string, paramsData, key string, value interface{}, root string) (string, error) {
-	props, err := ToMap(key, paramsData, root)
+// SetOpt is an option for setting a param.
+type SetOpt func(*setOptions)
+
+type setOptions struct {
+	valueType ValueType
+}
+
+// SetWithType sets the explicit type of the value. A value with an explicit
+// type can change the type of the current value; inferred types can't.
+func SetWithType(t ValueType) SetOpt {
+	return func(o *setOptions) {
+		o.valueType = t
+	}
+}
+
+// Set sets a param value. Only literal values can be set.
+func Set(path []string, paramsData, key string, value interface{}, root string, opts ...SetOpt) (string, error) {
+	pf, err := parseParams(paramsData)
+	if err != nil {
+		return "", err
+	}
+
+	return set(pf, path, key, value, root, opts...)
+}
+
+func set(pf *paramsFile, path []string, key string, value interface{}, root string, opts ...SetOpt) (string, error) {
+	var o setOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	props, err := pf.toMap(key, root)
 	if err != nil {
 		props = make(map[string]interface{})
 	}
 
-	changes := make(map[string]interface{})
-	cur := changes
+	base := updatePath(root, key)
+	if err = pf.checkLiteral(base, path, value); err != nil {
+		return "", err
+	}
 
-	for i, k := range path {
-		if i == len(path)-1 {
-			cur[k] = value
-		} else {
-			if _, ok := cur[k]; !ok {
-				m := make(map[string]interface{})
-				cur[k] = m
-				cur = m
-			}
-		}
+	if err = setValue(props, path, value, o.valueType); err != nil {
+		return "", err
 	}
 
-	if err = mergeMaps(props, changes, nil); err != nil {
+	if err = pf.set(append(base, path...), value); err != nil {
 		return "", err
 	}
 
-	return Update([]string{root, key}, paramsData, props)
+	return pf.print()
 }
 
-// Delete deletes a param value.
-func Delete(path []string, paramsData, key, root string) (string, error) {
-	props, err := ToMap(key, paramsData, root)
+// setValue checks that value can be set at path in props without changing
+// the type of the current value, unless the value has an explicit type.
+// Objects are merged.
+func setValue(props map[string]interface{}, path []string, value interface{}, t ValueType) error {
+	steps, err := parsePath(path)
 	if err != nil {
-		return "", err
+		return err
 	}
-	cur := props
 
-	for i, k := range path {
-		if i == len(path)-1 {
-			delete(cur, k)
-		} else {
-			m, ok := cur[k].(map[string]interface{})
+	var cur interface{} = props
+	for i, s := range steps {
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			if s.kind != stepKey {
+				return errors.Errorf("%s is not a list", pathString(steps[:i]))
+			}
+
+			v, ok := t[s.key]
 			if !ok {
-				return "", errors.New("path not found")
+				return nil
+			}
+
+			cur = v
+		case []interface{}:
+			if s.kind == stepKey {
+				return errors.Errorf("%s is a list", pathString(steps[:i]))
+			}
+
+			if s.kind == stepAppend {
+				return nil
 			}
 
-			cur = m
+			n, err := selectValue(t, s)
+			if err != nil {
+				return errors.Wrap(err, pathString(steps[:i]))
+			}
+
+			cur = t[n]
+		default:
+			return &typeConflictError{
+				path:    pathString(steps[:i]),
+				current: cur,
+				value:   map[string]interface{}{},
+			}
 		}
 	}
 
-	return Update([]string{root, key}, paramsData, props)
-}
-
-// Update updates a params file with the params for a component.
-func Update(path []string, src string, params map[string]interface{}) (string, error) {
-	obj, err := jsonnetutil.Parse("params.libsonnet", src)
-	if err != nil {
-		return "", errors.Wrap(err, "parse jsonnet")
+	if t != "" {
+		return nil
 	}
 
-	paramsObject, err := nm.KVFromMap(params)
-	if err != nil {
-		return "", errors.Wrap(err, "convert params to object")
+	m1, isMap1 := cur.(map[string]interface{})
+	m2, isMap2 := value.(map[string]interface{})
+	if isMap1 && isMap2 {
+		return mergeMaps(m1, m2, path)
 	}
 
-	if err := jsonnetutil.Set(obj, path, paramsObject.Node()); err != nil {
-		return "", errors.Wrap(err, "update params")
+	// The type of a secret isn't known until it is decrypted.
+	if !sameKind(cur, value) && !IsSecret(cur) && !IsSecret(value) {
+		return &typeConflictError{
+			path:    pathString(steps),
+			current: cur,
+			value:   value,
+		}
 	}
 
-	var buf bytes.Buffer
-	if err := printer.Fprint(&buf, obj); err != nil {
-		return "", errors.Wrap(err, "rebuild params")
+	return nil
+}
+
+// updatePath is the path to the params for a key. Globals have no key.
+func updatePath(root, key string) []string {
+	if key == "" {
+		return []string{root}
 	}
 
-	return buf.String(), nil
+	return []string{root, key}
 }
 
-// ToMap converts a component's params to a map.
-func ToMap(componentName, src, root string) (map[string]interface{}, error) {
-	obj, err := jsonnetutil.Parse("params.libsonnet", src)
+// Delete deletes a param value. Computed values can't be deleted.
+func Delete(path []string, paramsData, key, root string) (string, error) {
+	pf, err := parseParams(paramsData)
 	if err != nil {
-		return nil, errors.Wrap(err, "parse jsonnet")
+		return "", err
 	}
 
-	path := make([]string, 0)
-	if root != "" {
-		path = append(path, root)
+	return deleteParam(pf, path, key, root)
+}
+
+func deleteParam(pf *paramsFile, path []string, key, root string) (string, error) {
+	if _, err := pf.toMap(key, root); err != nil {
+		return "", err
 	}
 
-	if componentName != "" {
-		path = append(path, componentName)
+	base := updatePath(root, key)
+	if err := pf.checkLiteral(base, path, nil); err != nil {
+		return "", err
+	}
+
+	if err := pf.delete(append(base, path...)); err != nil {
+		return "", err
 	}
 
-	child, err := jsonnetutil.FindObject(obj, path)
+	return pf.print()
+}
+
+// Update updates a params file with the params for a component.
+func Update(path []string, src string, params map[string]interface{}) (string, error) {
+	pf, err := parseParams(src)
 	if err != nil {
-		return nil, errors.Wrapf(err, "find child paths for %s", strings.Join(path, "."))
+		return "", errors.Wrap(err, "parse jsonnet")
 	}
 
-	m, err := findValues(child)
+	paramsObject, err := valueNode(params)
 	if err != nil {
-		return nil, err
+		return "", errors.Wrap(err, "convert params to object")
 	}
 
-	if componentName == "" {
-		return m[root].(map[string]interface{}), nil
+	if err := jsonnetutil.Set(pf.obj, path, paramsObject); err != nil {
+		return "", errors.Wrap(err, "update params")
 	}
 
-	paramsMap, ok := m[componentName].(map[string]interface{})
-	if !ok {
-		return nil, errors.Errorf("could not find %q in components", componentName)
+	return pf.print()
+}
+
+// HasRoot reports if the params in src have a root object, e.g. `global`.
+func HasRoot(src, root string) (bool, error) {
+	pf, err := parseParams(src)
+	if err != nil {
+		return false, errors.Wrap(err, "parse jsonnet")
 	}
 
-	return paramsMap, nil
+	return pf.hasRoot(root)
+}
+
+// ToMap converts a component's params to a map. Params which aren't literals
+// are returned as Computed values.
+func ToMap(componentName, src, root string) (map[string]interface{}, error) {
+	pf, err := parseParams(src)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse jsonnet")
+	}
+
+	return pf.toMap(componentName, root)
 }
 
 var (
-	reFloat = regexp.MustCompile(`^([0-9]+[.])?[0-9]$`)
-	reInt   = regexp.MustCompile(`^[1-9]{1}[0-9]?$`)
+	reFloat = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+	reInt   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
 	reArray = regexp.MustCompile(`^\[`)
 	reMap   = regexp.MustCompile(`^\{`)
 )
 
-// DecodeValue decodes a string to an interface value.
+// DecodeValue decodes a string to an interface value. The type is inferred
+// unless the value starts with an explicit type, e.g. `string:true`.
 func DecodeValue(s string) (interface{}, error) {
 	if s == "" {
 		return nil, errors.New("value was blank")
 	}
 
+	if t, v, ok := SplitTypedValue(s); ok {
+		return DecodeTypedValue(v, t)
+	}
+
 	switch {
 	case reInt.MatchString(s):
-		return strconv.Atoi(s)
+		i, err := strconv.Atoi(s)
+		if isRangeError(err) {
+			// ints which don't fit are kept as strings, e.g. account ids
+			return s, nil
+		}
+		return i, err
 	case reFloat.MatchString(s):
 		return strconv.ParseFloat(s, 64)
 	case strings.ToLower(s) == "true" || strings.ToLower(s) == "false":
@@ -169,69 +256,63 @@ func DecodeValue(s string) (interface{}, error) {
 	}
 }
 
-func findValues(obj *astext.Object) (map[string]interface{}, error) {
-	m := make(map[string]interface{})
+func isRangeError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
 
-	for i := range obj.Fields {
-		id, err := jsonnetutil.FieldID(obj.Fields[i])
-		if err != nil {
-			return nil, err
+// literalValue returns the value of a literal node. Negative numbers, and
+// lists and objects of literals, are literals.
+func literalValue(node ast.Node) (interface{}, error) {
+	switch t := node.(type) {
+	case *ast.LiteralString:
+		return t.Value, nil
+	case *ast.LiteralBoolean:
+		return t.Value, nil
+	case *ast.LiteralNumber:
+		return t.Value, nil
+	case *ast.LiteralNull:
+		return nil, nil
+	case *ast.Unary:
+		if n, ok := t.Expr.(*ast.LiteralNumber); ok && t.Op == ast.UopMinus {
+			return -n.Value, nil
 		}
-
-		switch t := obj.Fields[i].Expr2.(type) {
-		default:
-			return nil, errors.Errorf("unknown value type %T", t)
-		case *ast.LiteralString, *ast.LiteralBoolean, *ast.LiteralNumber:
-			v, err := nodeValue(t)
+	case *ast.Array:
+		out := make([]interface{}, 0)
+		for i := range t.Elements {
+			v, err := literalValue(t.Elements[i])
 			if err != nil {
 				return nil, err
 			}
-			m[id] = v
-		case *ast.Array:
-			array, err := arrayValues(t)
+
+			out = append(out, v)
+		}
+
+		return out, nil
+	case *astext.Object:
+		out := make(map[string]interface{})
+		for i := range t.Fields {
+			if t.Fields[i].Kind == ast.ObjectLocal {
+				return nil, errors.New("objects with locals are not literals")
+			}
+
+			id, err := jsonnetutil.FieldID(t.Fields[i])
 			if err != nil {
 				return nil, err
 			}
-			m[id] = array
-		case *astext.Object:
-			child, err := findValues(t)
+
+			v, err := literalValue(t.Fields[i].Expr2)
 			if err != nil {
 				return nil, err
 			}
 
-			m[id] = child
+			out[id] = v
 		}
 
+		return out, nil
 	}
 
-	return m, nil
-}
-
-func nodeValue(node ast.Node) (interface{}, error) {
-	switch t := node.(type) {
-	default:
-		return nil, errors.Errorf("unknown value type %T", t)
-	case *ast.LiteralString:
-		return t.Value, nil
-	case *ast.LiteralBoolean:
-		return t.Value, nil
-	case *ast.LiteralNumber:
-		return t.Value, nil
-	}
-}
-
-func arrayValues(array *ast.Array) ([]interface{}, error) {
-	out := make([]interface{}, 0)
-	for i := range array.Elements {
-		v, err := nodeValue(array.Elements[i])
-		if err != nil {
-			return nil, errors.Errorf("arrays can't contain at %T", array.Elements[i])
-		}
-
-		out = append(out, v)
-	}
-
-	return out, nil
+	return nil, errors.Errorf("%T is not a literal", node)
 }
 
 func mergeMaps(m1 map[string]interface{}, m2 map[string]interface{}, path []string) error {
@@ -245,11 +326,15 @@ func mergeMaps(m1 map[string]interface{}, m2 map[string]interface{}, path []stri
 				if err != nil {
 					return err
 				}
-			} else if reflect.TypeOf(v1) == reflect.TypeOf(v2) {
+			} else if sameKind(m1[k], m2[k]) {
 				m1[k] = m2[k]
 			} else {
 				errorPath := append(path, k)
-				return fmt.Errorf("not same types at %s", strings.Join(errorPath, "."))
+				return &typeConflictError{
+					path:    strings.Join(errorPath, "."),
+					current: m1[k],
+					value:   m2[k],
+				}
 			}
 		} else {
 			m1[k] = m2[k]
@@ -258,3 +343,13 @@ func mergeMaps(m1 map[string]interface{}, m2 map[string]interface{}, path []stri
 
 	return nil
 }
+
+// sameKind returns true if v2 can replace v1 without changing its type. Null
+// values can be replaced by, and replace, any value.
+func sameKind(v1, v2 interface{}) bool {
+	if v1 == nil || v2 == nil {
+		return true
+	}
+
+	return kindOf(v1) == kindOf(v2)
+}
@@ -135,6 +135,86 @@ func TestDecodeValue(t *testing.T) {
 			val:      "foo",
 			expected: "foo",
 		},
+		{
+			name:     "large int",
+			val:      "100",
+			expected: 100,
+		},
+		{
+			name:     "int out of range",
+			val:      "99999999999999999999",
+			expected: "99999999999999999999",
+		},
+		{
+			name:  "typed int out of range",
+			val:   "int:99999999999999999999",
+			isErr: true,
+		},
+		{
+			name:     "negative int",
+			val:      "-3",
+			expected: -3,
+		},
+		{
+			name:     "zero",
+			val:      "0",
+			expected: 0,
+		},
+		{
+			name:     "float with multiple decimals",
+			val:      "0.25",
+			expected: 0.25,
+		},
+		{
+			name:     "float with exponent",
+			val:      "1e3",
+			expected: 1000.0,
+		},
+		{
+			name:     "leading zero",
+			val:      "0123",
+			expected: "0123",
+		},
+		{
+			name:     "version",
+			val:      "1.2.3",
+			expected: "1.2.3",
+		},
+		{
+			name:     "typed string",
+			val:      "string:true",
+			expected: "true",
+		},
+		{
+			name:     "typed int",
+			val:      "int:100",
+			expected: 100,
+		},
+		{
+			name:     "typed float",
+			val:      "float:1",
+			expected: 1.0,
+		},
+		{
+			name:     "typed bool",
+			val:      "bool:TRUE",
+			expected: true,
+		},
+		{
+			name:     "typed json",
+			val:      `json:"quoted"`,
+			expected: "quoted",
+		},
+		{
+			name:  "invalid typed int",
+			val:   "int:abc",
+			isErr: true,
+		},
+		{
+			name:     "string with colon",
+			val:      "gcr.io/image:1.0",
+			expected: "gcr.io/image:1.0",
+		},
 	}
 
 	for _, tc := range cases {
@@ -149,6 +229,11 @@ func TestDecodeValue(t *testing.T) {
 	}
 }
 
+func TestDecodeTypedValue_out_of_range(t *testing.T) {
+	_, err := DecodeTypedValue("99999999999999999999", TypeInt)
+	require.EqualError(t, err, `"99999999999999999999" is out of range for an int`)
+}
+
 func Test_mergeMaps(t *testing.T) {
 	m1 := map[string]interface{}{
 		"apiVersion": "apiextensions.k8s.io/v1beta1",
@@ -227,3 +312,98 @@ func Test_mergeMaps_simple(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, m1)
 }
+
+func Test_mergeMaps_type_conflict(t *testing.T) {
+	cases := []struct {
+		name     string
+		current  interface{}
+		value    interface{}
+		expected string
+	}{
+		{
+			name:     "number to string",
+			current:  float64(1),
+			value:    "100",
+			expected: "not same types at spec.replicas: current value is number, new value is string; set it with --type int to keep the current type or --type string to change it",
+		},
+		{
+			name:     "string to bool",
+			current:  "true",
+			value:    true,
+			expected: "not same types at spec.replicas: current value is string, new value is bool; set it with --type string to keep the current type or --type bool to change it",
+		},
+		{
+			name:     "float to string",
+			current:  0.25,
+			value:    "0.5",
+			expected: "not same types at spec.replicas: current value is number, new value is string; set it with --type float to keep the current type or --type string to change it",
+		},
+		{
+			name:     "object to string",
+			current:  map[string]interface{}{"a": "b"},
+			value:    "b",
+			expected: "not same types at spec.replicas: current value is object, new value is string; set it with --type json to keep the current type or --type string to change it",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m1 := map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": tc.current},
+			}
+			m2 := map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": tc.value},
+			}
+
+			err := mergeMaps(m1, m2, nil)
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func Test_mergeMaps_numbers(t *testing.T) {
+	m1 := map[string]interface{}{"replicas": float64(1)}
+	m2 := map[string]interface{}{"replicas": 3}
+
+	require.NoError(t, mergeMaps(m1, m2, nil))
+	require.Equal(t, 3, m1["replicas"])
+}
+
+func TestSet_type_change(t *testing.T) {
+	src := `{
+  components: {
+    web: {
+      replicas: "100",
+    },
+  },
+}
+`
+
+	_, err := Set([]string{"replicas"}, src, "web", 100, "components")
+	require.EqualError(t, err, "not same types at replicas: current value is string, new value is number; set it with --type string to keep the current type or --type int to change it")
+
+	got, err := Set([]string{"replicas"}, src, "web", 100, "components", SetWithType(TypeInt))
+	require.NoError(t, err)
+
+	m, err := ToMap("web", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, float64(100), m["replicas"])
+}
+
+func TestEnvSet_type_change(t *testing.T) {
+	src := `{
+  components+: {
+    web+: {
+      replicas: "100",
+    },
+  },
+}
+`
+
+	got, err := EnvSet([]string{"replicas"}, src, "web", 100, "components", SetWithType(TypeInt))
+	require.NoError(t, err)
+
+	m, err := EnvToMap("web", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, float64(100), m["replicas"])
+}
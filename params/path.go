@@ -0,0 +1,265 @@
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/pkg/errors"
+)
+
+type stepKind int
+
+const (
+	// stepKey is an object key, e.g. `image`.
+	stepKey stepKind = iota
+	// stepIndex is a list index, e.g. `[1]`.
+	stepIndex
+	// stepMatch selects the first list element with a key set to a value,
+	// e.g. `[name=http]`.
+	stepMatch
+	// stepAppend appends an element to a list, e.g. `[+]`.
+	stepAppend
+)
+
+// step is a step in a param path.
+type step struct {
+	kind  stepKind
+	key   string
+	index int
+	value string
+}
+
+func (s step) String() string {
+	switch s.kind {
+	case stepIndex:
+		return fmt.Sprintf("[%d]", s.index)
+	case stepMatch:
+		return fmt.Sprintf("[%s=%s]", s.key, s.value)
+	case stepAppend:
+		return "[+]"
+	default:
+		return s.key
+	}
+}
+
+// pathString formats steps as a path.
+func pathString(steps []step) string {
+	var sb strings.Builder
+	for i, s := range steps {
+		if i > 0 && s.kind == stepKey {
+			sb.WriteString(".")
+		}
+		sb.WriteString(s.String())
+	}
+
+	return sb.String()
+}
+
+// SplitPath splits a param path into segments. Dots in list selectors don't
+// split the path, e.g. `hosts[name=example.com].port`.
+func SplitPath(s string) []string {
+	var segments []string
+
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '.':
+			if depth == 0 {
+				segments = append(segments, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(segments, s[start:])
+}
+
+// parsePath parses path segments. A segment is a key followed by any number
+// of list selectors: `[1]` selects the element at an index, `[name=http]`
+// selects the first element whose name is http, and `[+]` appends an
+// element.
+func parsePath(path []string) ([]step, error) {
+	var steps []step
+	for _, segment := range path {
+		key := segment
+		selectors := ""
+		if i := strings.Index(segment, "["); i >= 0 {
+			key, selectors = segment[:i], segment[i:]
+		}
+
+		if key == "" && selectors == "" {
+			return nil, errors.Errorf("path %q has an empty segment", strings.Join(path, "."))
+		}
+
+		if key != "" {
+			steps = append(steps, step{kind: stepKey, key: key})
+		}
+
+		for selectors != "" {
+			end := strings.Index(selectors, "]")
+			if selectors[0] != '[' || end < 0 {
+				return nil, errors.Errorf("invalid list selector in %q", segment)
+			}
+
+			s, err := parseSelector(selectors[1:end])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid list selector in %q", segment)
+			}
+
+			steps = append(steps, s)
+			selectors = selectors[end+1:]
+		}
+	}
+
+	for i, s := range steps {
+		if s.kind == stepAppend && i != len(steps)-1 {
+			return nil, errors.Errorf("[+] must be at the end of path %q", strings.Join(path, "."))
+		}
+	}
+
+	return steps, nil
+}
+
+func parseSelector(s string) (step, error) {
+	if s == "+" {
+		return step{kind: stepAppend}, nil
+	}
+
+	if parts := strings.SplitN(s, "=", 2); len(parts) == 2 {
+		if parts[0] == "" {
+			return step{}, errors.New("selector key is blank")
+		}
+
+		return step{kind: stepMatch, key: parts[0], value: parts[1]}, nil
+	}
+
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return step{}, errors.Errorf("%q is not an index", s)
+	}
+
+	return step{kind: stepIndex, index: index}, nil
+}
+
+// stepKeys returns the keys of steps. ok is false if a step isn't a key.
+func stepKeys(steps []step) ([]string, bool) {
+	var out []string
+	for _, s := range steps {
+		if s.kind != stepKey {
+			return nil, false
+		}
+
+		out = append(out, s.key)
+	}
+
+	return out, true
+}
+
+// lastSelector returns the index of the last list selector in steps, or -1
+// if there isn't one.
+func lastSelector(steps []step) int {
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i].kind != stepKey {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// selectElement returns the index of the element in array selected by s.
+func selectElement(array *ast.Array, s step) (int, error) {
+	if s.kind == stepIndex {
+		if s.index >= len(array.Elements) {
+			return 0, errors.Errorf("index %d is out of range for a list of %d", s.index, len(array.Elements))
+		}
+
+		return s.index, nil
+	}
+
+	for i := range array.Elements {
+		obj, ok := array.Elements[i].(*astext.Object)
+		if !ok {
+			continue
+		}
+
+		field, err := findField(obj, s.key)
+		if err != nil {
+			continue
+		}
+
+		if v, err := literalValue(field.Expr2); err == nil && fmt.Sprint(v) == s.value {
+			return i, nil
+		}
+	}
+
+	return 0, errors.Errorf("no element has %s=%s", s.key, s.value)
+}
+
+// selectValue returns the index of the element in list selected by s.
+func selectValue(list []interface{}, s step) (int, error) {
+	if s.kind == stepIndex {
+		if s.index >= len(list) {
+			return 0, errors.Errorf("index %d is out of range for a list of %d", s.index, len(list))
+		}
+
+		return s.index, nil
+	}
+
+	for i := range list {
+		m, ok := list[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if v, ok := m[s.key]; ok && fmt.Sprint(v) == s.value {
+			return i, nil
+		}
+	}
+
+	return 0, errors.Errorf("no element has %s=%s", s.key, s.value)
+}
+
+// walk follows steps from obj to a node.
+func walk(obj *astext.Object, steps []step) (ast.Node, error) {
+	var cur ast.Node = obj
+	for i, s := range steps {
+		switch t := cur.(type) {
+		case *astext.Object:
+			if s.kind != stepKey {
+				return nil, errors.Errorf("%s is not a list", pathString(steps[:i]))
+			}
+
+			field, err := findField(t, s.key)
+			if err != nil {
+				return nil, errors.Errorf("%s was not found", pathString(steps[:i+1]))
+			}
+
+			cur = field.Expr2
+		case *ast.Array:
+			if s.kind != stepIndex && s.kind != stepMatch {
+				return nil, errors.Errorf("%s is a list", pathString(steps[:i]))
+			}
+
+			n, err := selectElement(t, s)
+			if err != nil {
+				return nil, errors.Wrap(err, pathString(steps[:i]))
+			}
+
+			cur = t.Elements[n]
+		default:
+			return nil, errors.Errorf("%s is not an object or a list", pathString(steps[:i]))
+		}
+	}
+
+	return cur, nil
+}
@@ -0,0 +1,105 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "image", expected: []string{"image"}},
+		{path: "metadata.labels.app", expected: []string{"metadata", "labels", "app"}},
+		{path: "containers[1].env", expected: []string{"containers[1]", "env"}},
+		{path: "hosts[name=example.com].port", expected: []string{"hosts[name=example.com]", "port"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			require.Equal(t, tc.expected, SplitPath(tc.path))
+		})
+	}
+}
+
+func Test_parsePath(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     []string
+		expected []step
+		isErr    bool
+	}{
+		{
+			name: "keys",
+			path: []string{"metadata", "name"},
+			expected: []step{
+				{kind: stepKey, key: "metadata"},
+				{kind: stepKey, key: "name"},
+			},
+		},
+		{
+			name: "selectors",
+			path: []string{"containers[1]", "ports[name=http]", "port"},
+			expected: []step{
+				{kind: stepKey, key: "containers"},
+				{kind: stepIndex, index: 1},
+				{kind: stepKey, key: "ports"},
+				{kind: stepMatch, key: "name", value: "http"},
+				{kind: stepKey, key: "port"},
+			},
+		},
+		{
+			name: "nested lists",
+			path: []string{"matrix[0][1]"},
+			expected: []step{
+				{kind: stepKey, key: "matrix"},
+				{kind: stepIndex, index: 0},
+				{kind: stepIndex, index: 1},
+			},
+		},
+		{
+			name: "append",
+			path: []string{"ports[+]"},
+			expected: []step{
+				{kind: stepKey, key: "ports"},
+				{kind: stepAppend},
+			},
+		},
+		{
+			name:  "append before the end",
+			path:  []string{"ports[+]", "port"},
+			isErr: true,
+		},
+		{
+			name:  "unclosed selector",
+			path:  []string{"ports[1"},
+			isErr: true,
+		},
+		{
+			name:  "negative index",
+			path:  []string{"ports[-1]"},
+			isErr: true,
+		},
+		{
+			name:  "empty segment",
+			path:  []string{"metadata", ""},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parsePath(tc.path)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+			require.Equal(t, tc.path, SplitPath(pathString(got)))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package params
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PromotionStatus describes what promoting an override does to the target.
+type PromotionStatus string
+
+const (
+	// PromoteAdd is an override the target does not have.
+	PromoteAdd PromotionStatus = "add"
+	// PromoteUnchanged is an override the target already has.
+	PromoteUnchanged PromotionStatus = "unchanged"
+	// PromoteConflict is an override the target has with a different value.
+	PromoteConflict PromotionStatus = "conflict"
+)
+
+// Promotion is an environment override copied to another environment.
+// Nested keys are joined with dots in Key, and kept as segments in Path.
+type Promotion struct {
+	Component string
+	Key       string
+	Path      []string
+	Value     interface{}
+	Current   interface{}
+	Status    PromotionStatus
+}
+
+// PlanPromotion compares the component overrides in the from and to
+// environment params. Overrides can be limited to components and keys. A
+// key also selects the keys nested below it.
+func PlanPromotion(from, to string, components, keys []string) ([]Promotion, error) {
+	fromOverrides, err := EnvToMap("", from, "components")
+	if err != nil {
+		return nil, errors.Wrap(err, "read source overrides")
+	}
+
+	toOverrides, err := EnvToMap("", to, "components")
+	if err != nil {
+		return nil, errors.Wrap(err, "read target overrides")
+	}
+
+	var promotions []Promotion
+	for entry, v := range fromOverrides {
+		if !selected(entry, components, matchesEntry) {
+			continue
+		}
+
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("overrides for %q are not an object", entry)
+		}
+
+		var current map[string]flatParam
+		if cur, ok := toOverrides[entry].(map[string]interface{}); ok {
+			current = flatten(cur, nil)
+		}
+
+		for key, param := range flatten(m, nil) {
+			if !selected(key, keys, matchesKey) {
+				continue
+			}
+
+			p := Promotion{Component: entry, Key: key, Path: param.path, Value: param.value, Status: PromoteAdd}
+			if cur, ok := current[key]; ok {
+				p.Current = cur.value
+				p.Status = PromoteConflict
+				if reflect.DeepEqual(cur.value, param.value) {
+					p.Status = PromoteUnchanged
+				}
+			}
+
+			promotions = append(promotions, p)
+		}
+	}
+
+	sort.Slice(promotions, func(i, j int) bool {
+		if promotions[i].Component != promotions[j].Component {
+			return promotions[i].Component < promotions[j].Component
+		}
+		return promotions[i].Key < promotions[j].Key
+	})
+
+	return promotions, nil
+}
+
+// Promote writes promotions to environment params. Conflicts are only
+// written if overwrite is true.
+func Promote(src string, promotions []Promotion, overwrite bool) (string, error) {
+	var err error
+	for _, p := range promotions {
+		switch p.Status {
+		case PromoteUnchanged:
+			continue
+		case PromoteConflict:
+			if !overwrite {
+				continue
+			}
+		}
+
+		src, err = EnvSet(p.Path, src, p.Component, p.Value, "components")
+		if err != nil {
+			return "", errors.Wrapf(err, "promote %s %s", p.Component, p.Key)
+		}
+	}
+
+	return src, nil
+}
+
+func selected(s string, filters []string, match func(string, string) bool) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, filter := range filters {
+		if match(s, filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var reEntryIndex = regexp.MustCompile(`-\d+$`)
+
+// matchesEntry matches a params entry to a component name. YAML components
+// have an entry for each object, e.g. `deployment-0`.
+func matchesEntry(entry, name string) bool {
+	return entry == name || reEntryIndex.ReplaceAllString(entry, "") == name
+}
+
+func matchesKey(key, filter string) bool {
+	return key == filter || strings.HasPrefix(key, filter+".")
+}
@@ -0,0 +1,128 @@
+package params
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readPromotionFixtures(t *testing.T) (string, string) {
+	from, err := ioutil.ReadFile("testdata/env-params-staging.libsonnet")
+	require.NoError(t, err)
+
+	to, err := ioutil.ReadFile("testdata/env-params-production.libsonnet")
+	require.NoError(t, err)
+
+	return string(from), string(to)
+}
+
+func TestPlanPromotion(t *testing.T) {
+	from, to := readPromotionFixtures(t)
+
+	cases := []struct {
+		name       string
+		components []string
+		keys       []string
+		expected   []Promotion
+	}{
+		{
+			name: "all overrides",
+			expected: []Promotion{
+				{Component: "deployment-0", Key: "replicas", Path: []string{"replicas"}, Value: float64(2), Status: PromoteAdd},
+				{Component: "guestbook-ui", Key: "image", Path: []string{"image"}, Value: "guestbook:0.3", Status: PromoteAdd},
+				{Component: "guestbook-ui", Key: "labels.tier", Path: []string{"labels", "tier"}, Value: "frontend", Current: "frontend", Status: PromoteUnchanged},
+				{Component: "guestbook-ui", Key: "replicas", Path: []string{"replicas"}, Value: float64(2), Current: float64(5), Status: PromoteConflict},
+			},
+		},
+		{
+			name:       "yaml component",
+			components: []string{"deployment"},
+			expected: []Promotion{
+				{Component: "deployment-0", Key: "replicas", Path: []string{"replicas"}, Value: float64(2), Status: PromoteAdd},
+			},
+		},
+		{
+			name:       "keys",
+			components: []string{"guestbook-ui"},
+			keys:       []string{"image", "labels"},
+			expected: []Promotion{
+				{Component: "guestbook-ui", Key: "image", Path: []string{"image"}, Value: "guestbook:0.3", Status: PromoteAdd},
+				{Component: "guestbook-ui", Key: "labels.tier", Path: []string{"labels", "tier"}, Value: "frontend", Current: "frontend", Status: PromoteUnchanged},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PlanPromotion(from, to, tc.components, tc.keys)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestPromote(t *testing.T) {
+	from, to := readPromotionFixtures(t)
+
+	promotions, err := PlanPromotion(from, to, []string{"guestbook-ui"}, nil)
+	require.NoError(t, err)
+
+	got, err := Promote(to, promotions, false)
+	require.NoError(t, err)
+
+	m, err := EnvToMap("guestbook-ui", got, "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"image":    "guestbook:0.3",
+		"replicas": float64(5),
+		"labels": map[string]interface{}{
+			"tier": "frontend",
+		},
+	}
+	require.Equal(t, expected, m)
+
+	got, err = Promote(to, promotions, true)
+	require.NoError(t, err)
+
+	m, err = EnvToMap("guestbook-ui", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, float64(2), m["replicas"])
+}
+
+func TestPromote_dotted_keys(t *testing.T) {
+	from := `{
+  components+: {
+    web+: {
+      annotations+: {
+        "prometheus.io/scrape": "true",
+      },
+    },
+  },
+}
+`
+	to := `{
+  components+: {},
+}
+`
+
+	promotions, err := PlanPromotion(from, to, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, promotions, 1)
+	require.Equal(t, []string{"annotations", "prometheus.io/scrape"}, promotions[0].Path)
+
+	got, err := Promote(to, promotions, false)
+	require.NoError(t, err)
+
+	m, err := EnvToMap("web", got, "components")
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"annotations": map[string]interface{}{
+			"prometheus.io/scrape": "true",
+		},
+	}
+	require.Equal(t, expected, m)
+}
@@ -0,0 +1,547 @@
+package params
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+)
+
+// edit replaces the source between begin and end with text.
+type edit struct {
+	begin int
+	end   int
+	text  string
+}
+
+func newParamsFile(src string, obj *astext.Object) *paramsFile {
+	pf := &paramsFile{
+		src:         src,
+		obj:         obj,
+		lineOffsets: []int{0},
+		fields:      make(map[*astext.Object][]astext.ObjectField),
+		elements:    make(map[*ast.Array][]ast.Node),
+	}
+
+	for i := range src {
+		if src[i] == '\n' {
+			pf.lineOffsets = append(pf.lineOffsets, i+1)
+		}
+	}
+
+	pf.record(obj)
+
+	return pf
+}
+
+// record records the fields of objects and elements of lists in node.
+func (pf *paramsFile) record(node ast.Node) {
+	switch t := node.(type) {
+	case *astext.Object:
+		pf.fields[t] = append([]astext.ObjectField{}, t.Fields...)
+		for i := range t.Fields {
+			pf.record(t.Fields[i].Expr2)
+		}
+	case *ast.Array:
+		pf.elements[t] = append([]ast.Node{}, t.Elements...)
+		for i := range t.Elements {
+			pf.record(t.Elements[i])
+		}
+	}
+}
+
+// print writes the changes to the AST back to the source. Only the text of
+// changed fields and list elements is replaced.
+func (pf *paramsFile) print() (string, error) {
+	var edits []edit
+	if err := pf.diff(pf.obj, &edits); err != nil {
+		return "", err
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].begin < edits[j].begin
+	})
+
+	var sb strings.Builder
+	last := 0
+	for _, e := range edits {
+		if e.begin < last {
+			return "", errors.New("params edits overlap")
+		}
+
+		sb.WriteString(pf.src[last:e.begin])
+		sb.WriteString(e.text)
+		last = e.end
+	}
+	sb.WriteString(pf.src[last:])
+
+	return sb.String(), nil
+}
+
+// diff finds the edits for the changes to a parsed object or list.
+func (pf *paramsFile) diff(node ast.Node, edits *[]edit) error {
+	switch t := node.(type) {
+	case *astext.Object:
+		if original, ok := pf.fields[t]; ok {
+			return pf.diffObject(t, original, edits)
+		}
+	case *ast.Array:
+		if original, ok := pf.elements[t]; ok {
+			return pf.diffArray(t, original, edits)
+		}
+	}
+
+	return nil
+}
+
+func (pf *paramsFile) diffObject(obj *astext.Object, original []astext.ObjectField, edits *[]edit) error {
+	current := make(map[string]*astext.ObjectField)
+	for i := range obj.Fields {
+		current[fieldKey(obj.Fields[i])] = &obj.Fields[i]
+	}
+
+	parsed := make(map[string]bool)
+	for _, field := range original {
+		key := fieldKey(field)
+		parsed[key] = true
+
+		cur, ok := current[key]
+		switch {
+		case !ok:
+			*edits = append(*edits, pf.removal(pf.fieldBegin(field), pf.end(field.Expr2)))
+		case cur.Expr2 != field.Expr2:
+			text, err := pf.render(cur.Expr2, pf.begin(field.Expr2))
+			if err != nil {
+				return err
+			}
+
+			*edits = append(*edits, edit{begin: pf.begin(field.Expr2), end: pf.end(field.Expr2), text: text})
+		default:
+			if err := pf.diff(cur.Expr2, edits); err != nil {
+				return err
+			}
+		}
+	}
+
+	var added []string
+	for i := range obj.Fields {
+		if parsed[fieldKey(obj.Fields[i])] {
+			continue
+		}
+
+		text, err := renderField(obj.Fields[i])
+		if err != nil {
+			return err
+		}
+
+		added = append(added, text)
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	prevBegin, prevEnd := -1, -1
+	if len(original) > 0 {
+		prev := original[len(original)-1]
+		prevBegin, prevEnd = pf.fieldBegin(prev), pf.end(prev.Expr2)
+	}
+
+	*edits = append(*edits, pf.insertion(obj, prevBegin, prevEnd, added, true)...)
+	return nil
+}
+
+func (pf *paramsFile) diffArray(array *ast.Array, original []ast.Node, edits *[]edit) error {
+	current := make(map[ast.Node]bool)
+	for _, element := range array.Elements {
+		current[element] = true
+	}
+
+	written := make(map[ast.Node]bool)
+	for i, element := range original {
+		written[element] = true
+
+		switch {
+		case current[element]:
+			if err := pf.diff(element, edits); err != nil {
+				return err
+			}
+		case len(array.Elements) == len(original):
+			// The element was replaced.
+			text, err := pf.render(array.Elements[i], pf.begin(element))
+			if err != nil {
+				return err
+			}
+
+			written[array.Elements[i]] = true
+			*edits = append(*edits, edit{begin: pf.begin(element), end: pf.end(element), text: text})
+		default:
+			*edits = append(*edits, pf.removal(pf.begin(element), pf.end(element)))
+		}
+	}
+
+	var added []string
+	for _, element := range array.Elements {
+		if written[element] {
+			continue
+		}
+
+		text, err := printValue(element)
+		if err != nil {
+			return err
+		}
+
+		added = append(added, text)
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	prevBegin, prevEnd := -1, -1
+	if len(original) > 0 {
+		prev := original[len(original)-1]
+		prevBegin, prevEnd = pf.begin(prev), pf.end(prev)
+	}
+
+	*edits = append(*edits, pf.insertion(array, prevBegin, prevEnd, added, false)...)
+	return nil
+}
+
+// insertion inserts items before the closing bracket of an object or list.
+// The items follow the item between prevBegin and prevEnd, if there is one.
+// Items are written on their own lines unless the object or list is written
+// on one line. Empty objects are always written on multiple lines.
+func (pf *paramsFile) insertion(node ast.Node, prevBegin, prevEnd int, items []string, isObject bool) []edit {
+	open, closing := pf.begin(node), pf.end(node)-1
+
+	comma := -1
+	if prevEnd >= 0 {
+		comma = pf.nextComma(prevEnd, closing)
+	}
+
+	var edits []edit
+
+	closingLine := pf.lineBegin(closing)
+	if strings.TrimSpace(pf.src[closingLine:closing]) == "" {
+		indent := pf.indent(closing) + "  "
+		if prevBegin >= 0 {
+			indent = pf.indent(prevBegin)
+		}
+
+		if prevEnd >= 0 && comma < 0 {
+			edits = append(edits, edit{begin: prevEnd, end: prevEnd, text: ","})
+		}
+
+		var sb strings.Builder
+		for _, item := range items {
+			sb.WriteString(indent + indentLines(item, indent) + ",\n")
+		}
+
+		return append(edits, edit{begin: closingLine, end: closingLine, text: sb.String()})
+	}
+
+	if prevEnd < 0 {
+		multiline := isObject
+		for _, item := range items {
+			multiline = multiline || strings.Contains(item, "\n")
+		}
+
+		if !multiline {
+			return []edit{{begin: open + 1, end: closing, text: strings.Join(items, ", ")}}
+		}
+
+		lineIndent := pf.indent(open)
+		indent := lineIndent + "  "
+
+		var sb strings.Builder
+		sb.WriteString("\n")
+		for _, item := range items {
+			sb.WriteString(indent + indentLines(item, indent) + ",\n")
+		}
+		sb.WriteString(lineIndent)
+
+		return []edit{{begin: open + 1, end: closing, text: sb.String()}}
+	}
+
+	if comma < 0 {
+		return []edit{{begin: prevEnd, end: prevEnd, text: ", " + strings.Join(items, ", ")}}
+	}
+
+	return []edit{{begin: comma + 1, end: comma + 1, text: " " + strings.Join(items, ", ") + ","}}
+}
+
+// removal removes the item between begin and end with its comma. If the item
+// is on lines of its own, the lines are removed. If it is the sole item of a
+// one-line object or array, the object or array is left empty.
+func (pf *paramsFile) removal(begin, end int) edit {
+	src := pf.src
+
+	hasComma := false
+	if i := pf.nextComma(end, len(src)); i >= 0 {
+		end, hasComma = i+1, true
+	}
+
+	lineBegin := pf.lineBegin(begin)
+	lineEnd := strings.IndexByte(src[end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(src)
+	} else {
+		lineEnd += end
+	}
+
+	after := strings.TrimSpace(src[end:lineEnd])
+	if strings.TrimSpace(src[lineBegin:begin]) == "" &&
+		(after == "" || strings.HasPrefix(after, "//") || strings.HasPrefix(after, "#")) {
+		if lineEnd < len(src) {
+			lineEnd++
+		}
+
+		return edit{begin: lineBegin, end: lineEnd}
+	}
+
+	// The sole item on a line, e.g. `a` in `{ a: 1 }`, leaves `{}`.
+	open, closing := begin, end
+	for open > 0 && isSpace(src[open-1]) {
+		open--
+	}
+	for closing < len(src) && isSpace(src[closing]) {
+		closing++
+	}
+	if open > 0 && closing < len(src) &&
+		((src[open-1] == '{' && src[closing] == '}') || (src[open-1] == '[' && src[closing] == ']')) {
+		return edit{begin: open, end: closing}
+	}
+
+	if hasComma {
+		for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+			end++
+		}
+
+		return edit{begin: begin, end: end}
+	}
+
+	// The last item on a line, e.g. `b` in `{ a: 1, b: 2 }`, takes the comma
+	// before it.
+	i := begin
+	for i > 0 && isSpace(src[i-1]) {
+		i--
+	}
+
+	if i > 0 && src[i-1] == ',' {
+		begin = i - 1
+	}
+
+	return edit{begin: begin, end: end}
+}
+
+// nextComma returns the offset of a comma between from and to which only has
+// whitespace and comments before it, or -1.
+func (pf *paramsFile) nextComma(from, to int) int {
+	src := pf.src
+	for i := from; i < to; i++ {
+		switch {
+		case isSpace(src[i]):
+		case src[i] == ',':
+			return i
+		case src[i] == '#' || strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				return -1
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i:], "*/")
+			if end < 0 {
+				return -1
+			}
+			i += end + 1
+		default:
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// fieldBegin returns the offset of the start of a field's key.
+func (pf *paramsFile) fieldBegin(field astext.ObjectField) int {
+	src := pf.src
+	i := pf.begin(field.Expr2)
+	back := func(match func(byte) bool) {
+		for i > 0 && match(src[i-1]) {
+			i--
+		}
+	}
+
+	back(isSpace)
+	if field.Kind == ast.ObjectLocal {
+		// local name = value
+		back(func(c byte) bool { return c == '=' })
+		back(isSpace)
+		back(isIdentifier)
+		back(isSpace)
+		back(isIdentifier)
+		return i
+	}
+
+	back(func(c byte) bool { return c == ':' || c == '+' })
+	back(isSpace)
+
+	if i > 0 && (src[i-1] == '"' || src[i-1] == '\'') {
+		quote := src[i-1]
+		for i--; i > 0; i-- {
+			if src[i-1] == quote && (i < 2 || src[i-2] != '\\') {
+				return i - 1
+			}
+		}
+
+		return i
+	}
+
+	back(isIdentifier)
+	return i
+}
+
+func (pf *paramsFile) offset(loc ast.Location) int {
+	return pf.lineOffsets[loc.Line-1] + loc.Column - 1
+}
+
+func (pf *paramsFile) begin(node ast.Node) int {
+	return pf.offset(node.Loc().Begin)
+}
+
+func (pf *paramsFile) end(node ast.Node) int {
+	return pf.offset(node.Loc().End)
+}
+
+// lineBegin returns the offset of the line containing offset.
+func (pf *paramsFile) lineBegin(offset int) int {
+	return strings.LastIndexByte(pf.src[:offset], '\n') + 1
+}
+
+// indent returns the indent of the line containing offset.
+func (pf *paramsFile) indent(offset int) string {
+	line := pf.src[pf.lineBegin(offset):]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// render prints a node which replaces the source at offset.
+func (pf *paramsFile) render(node ast.Node, offset int) (string, error) {
+	text, err := printValue(node)
+	if err != nil {
+		return "", err
+	}
+
+	return indentLines(text, pf.indent(offset)), nil
+}
+
+// renderField prints a field without its trailing comma.
+func renderField(field astext.ObjectField) (string, error) {
+	text, err := printValue(&astext.Object{Fields: []astext.ObjectField{field}})
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) < 3 {
+		return "", errors.Errorf("unable to print field %q", fieldKey(field))
+	}
+
+	lines = lines[1 : len(lines)-1]
+	for i := range lines {
+		lines[i] = strings.TrimPrefix(lines[i], "  ")
+	}
+
+	return strings.TrimSuffix(strings.Join(lines, "\n"), ","), nil
+}
+
+// printValue prints a node which isn't in the source. Lists with an element
+// which spans lines are printed with one element per line.
+func printValue(node ast.Node) (string, error) {
+	switch t := node.(type) {
+	case *ast.Array:
+		var elements []string
+		multiline := false
+		for i := range t.Elements {
+			element, err := printValue(t.Elements[i])
+			if err != nil {
+				return "", err
+			}
+
+			multiline = multiline || strings.Contains(element, "\n")
+			elements = append(elements, element)
+		}
+
+		if !multiline {
+			return "[" + strings.Join(elements, ", ") + "]", nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString("[\n")
+		for _, element := range elements {
+			sb.WriteString("  " + indentLines(element, "  ") + ",\n")
+		}
+		sb.WriteString("]")
+
+		return sb.String(), nil
+	case *astext.Object:
+		// The printer doesn't print lists the same way, so they are printed
+		// first.
+		for i := range t.Fields {
+			if _, ok := t.Fields[i].Expr2.(*ast.Array); !ok {
+				if _, ok := t.Fields[i].Expr2.(*astext.Object); !ok {
+					continue
+				}
+			}
+
+			text, err := printValue(t.Fields[i].Expr2)
+			if err != nil {
+				return "", err
+			}
+
+			t.Fields[i].Expr2 = &ast.Var{Id: ast.Identifier(text)}
+		}
+
+		quoteExtendedFields(t)
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, node); err != nil {
+		return "", errors.Wrap(err, "print params")
+	}
+
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// fieldKey identifies a field in an object.
+func fieldKey(field astext.ObjectField) string {
+	if field.Kind == ast.ObjectLocal && field.Id != nil {
+		return "local " + string(*field.Id)
+	}
+
+	id, err := jsonnetutil.FieldID(field)
+	if err != nil {
+		return ""
+	}
+
+	return id
+}
+
+// indentLines indents the lines of text after the first.
+func indentLines(text, indent string) string {
+	return strings.Replace(text, "\n", "\n"+indent, -1)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
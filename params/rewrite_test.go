@@ -0,0 +1,172 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const commentedParams = `local registry = "gcr.io/example";
+
+{
+  // Global params.
+  global: {},
+  components: {
+    web: {
+      image: registry + "/web", // built by CI
+      replicas: 1,   // scaled by hand
+      ports: [80, 443],
+      labels: {app: "web"},
+    },
+  },
+}
+`
+
+func TestSet_preservesSource(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		value    interface{}
+		expected string
+	}{
+		{
+			name:  "replace a value",
+			path:  "replicas",
+			value: 3,
+			expected: `local registry = "gcr.io/example";
+
+{
+  // Global params.
+  global: {},
+  components: {
+    web: {
+      image: registry + "/web", // built by CI
+      replicas: 3,   // scaled by hand
+      ports: [80, 443],
+      labels: {app: "web"},
+    },
+  },
+}
+`,
+		},
+		{
+			name:  "add a field",
+			path:  "name",
+			value: "frontend",
+			expected: `local registry = "gcr.io/example";
+
+{
+  // Global params.
+  global: {},
+  components: {
+    web: {
+      image: registry + "/web", // built by CI
+      replicas: 1,   // scaled by hand
+      ports: [80, 443],
+      labels: {app: "web"},
+      name: "frontend",
+    },
+  },
+}
+`,
+		},
+		{
+			name:  "append to an inline list",
+			path:  "ports[+]",
+			value: 8080,
+			expected: `local registry = "gcr.io/example";
+
+{
+  // Global params.
+  global: {},
+  components: {
+    web: {
+      image: registry + "/web", // built by CI
+      replicas: 1,   // scaled by hand
+      ports: [80, 443, 8080],
+      labels: {app: "web"},
+    },
+  },
+}
+`,
+		},
+		{
+			name:  "add to an inline object",
+			path:  "labels.tier",
+			value: "frontend",
+			expected: `local registry = "gcr.io/example";
+
+{
+  // Global params.
+  global: {},
+  components: {
+    web: {
+      image: registry + "/web", // built by CI
+      replicas: 1,   // scaled by hand
+      ports: [80, 443],
+      labels: {app: "web", tier: "frontend"},
+    },
+  },
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Set(SplitPath(tc.path), commentedParams, "web", tc.value, "components")
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestDelete_preservesSource(t *testing.T) {
+	got, err := Delete([]string{"replicas"}, commentedParams, "web", "components")
+	require.NoError(t, err)
+
+	expected := `local registry = "gcr.io/example";
+
+{
+  // Global params.
+  global: {},
+  components: {
+    web: {
+      image: registry + "/web", // built by CI
+      ports: [80, 443],
+      labels: {app: "web"},
+    },
+  },
+}
+`
+
+	require.Equal(t, expected, got)
+}
+
+func TestDelete_sole_member(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "object",
+			src:      "{\n  components: {\n    web: { a: 1 },\n  },\n}\n",
+			expected: "{\n  components: {\n    web: {},\n  },\n}\n",
+		},
+		{
+			name:     "object with comma",
+			src:      "{\n  components: {\n    web: {a: 1,},\n  },\n}\n",
+			expected: "{\n  components: {\n    web: {},\n  },\n}\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Delete([]string{"a"}, tc.src, "web", "components")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
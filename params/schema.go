@@ -0,0 +1,255 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Schema declares the params a component accepts. It is a subset of JSON
+// schema. Unlike JSON schema, an object with properties rejects keys it does
+// not declare unless additionalProperties is true, so typos are caught.
+type Schema struct {
+	// Type is one of string, integer, number, boolean, array or object.
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+// ParseSchema parses a JSON encoded schema.
+func ParseSchema(b []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, errors.Wrap(err, "decode schema")
+	}
+
+	return &s, nil
+}
+
+// SchemaError is a param which does not match its schema.
+type SchemaError struct {
+	Path    string
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Lookup returns the schema for the param at path. List selectors in the path
+// look up the schema of the list's items. It returns an error if the path is
+// not declared.
+func (s *Schema) Lookup(path []string) (*Schema, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, SchemaError{Path: strings.Join(path, "."), Message: err.Error()}
+	}
+
+	cur := s
+	for i, st := range steps {
+		if st.kind != stepKey {
+			if cur.Items == nil {
+				return nil, nil
+			}
+
+			cur = cur.Items
+			continue
+		}
+
+		child, ok := cur.Properties[st.key]
+		if !ok {
+			if cur.allowsAdditional() {
+				return nil, nil
+			}
+
+			return nil, SchemaError{
+				Path:    pathString(steps[:i+1]),
+				Message: fmt.Sprintf("unknown param; expected one of %s", strings.Join(cur.propertyNames(), ", ")),
+			}
+		}
+
+		cur = child
+	}
+
+	return cur, nil
+}
+
+// ValidateValue validates a value which will be set at path.
+func (s *Schema) ValidateValue(path []string, value interface{}) []SchemaError {
+	child, err := s.Lookup(path)
+	if err != nil {
+		return []SchemaError{err.(SchemaError)}
+	}
+
+	if child == nil {
+		return nil
+	}
+
+	return child.validate(path, value, nil, false)
+}
+
+// Validate validates a component's params. Globals are the global params
+// which were applied to the params; undeclared keys which come from globals
+// are not unknown params.
+func (s *Schema) Validate(values, globals map[string]interface{}) []SchemaError {
+	return s.validate(nil, values, globals, true)
+}
+
+func (s *Schema) validate(path []string, value, globals interface{}, checkRequired bool) []SchemaError {
+	pathStr := strings.Join(path, ".")
+
+	if s.Type != "" && !matchesType(s.Type, value) {
+		return []SchemaError{{Path: pathStr, Message: fmt.Sprintf("expected %s, got %s", s.Type, kindOf(value))}}
+	}
+
+	var errs []SchemaError
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		errs = append(errs, SchemaError{Path: pathStr, Message: fmt.Sprintf("must be one of %s", enumString(s.Enum))})
+	}
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		globalValues, _ := globals.(map[string]interface{})
+
+		for _, k := range keys {
+			childPath := append(append([]string{}, path...), k)
+			global, isGlobal := globalValues[k]
+			child, ok := s.Properties[k]
+			if !ok {
+				if !s.allowsAdditional() && !isGlobal {
+					errs = append(errs, SchemaError{
+						Path:    strings.Join(childPath, "."),
+						Message: fmt.Sprintf("unknown param; expected one of %s", strings.Join(s.propertyNames(), ", ")),
+					})
+				}
+				continue
+			}
+
+			errs = append(errs, child.validate(childPath, t[k], global, checkRequired)...)
+		}
+
+		if checkRequired {
+			for _, k := range s.Required {
+				if _, ok := t[k]; !ok {
+					errs = append(errs, SchemaError{
+						Path:    strings.Join(append(append([]string{}, path...), k), "."),
+						Message: "required param is missing",
+					})
+				}
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range t {
+				itemPath := append(append([]string{}, path...), fmt.Sprintf("[%d]", i))
+				errs = append(errs, s.Items.validate(itemPath, item, nil, checkRequired)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) allowsAdditional() bool {
+	if len(s.Properties) == 0 {
+		return true
+	}
+
+	return s.AdditionalProperties != nil && *s.AdditionalProperties
+}
+
+func (s *Schema) propertyNames() []string {
+	var names []string
+	for k := range s.Properties {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func matchesType(t string, value interface{}) bool {
+	switch t {
+	case "integer":
+		f, ok := toFloat(value)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "boolean":
+		return kindOf(value) == "bool"
+	default:
+		return kindOf(value) == t
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case float32:
+		return float64(t), true
+	case float64:
+		return t, true
+	default:
+		return 0, false
+	}
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		ef, eIsNum := toFloat(e)
+		vf, vIsNum := toFloat(value)
+		if eIsNum && vIsNum {
+			if ef == vf {
+				return true
+			}
+			continue
+		}
+
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func enumString(enum []interface{}) string {
+	var values []string
+	for _, e := range enum {
+		b, err := json.Marshal(e)
+		if err != nil {
+			values = append(values, fmt.Sprintf("%v", e))
+			continue
+		}
+		values = append(values, string(b))
+	}
+
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,124 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = []byte(`{
+  "properties": {
+    "image": {"type": "string", "description": "container image"},
+    "replicas": {"type": "integer", "default": 1},
+    "tier": {"type": "string", "enum": ["frontend", "backend"]},
+    "labels": {"type": "object", "additionalProperties": true},
+    "ports": {"type": "array", "items": {"type": "integer"}},
+    "resources": {
+      "type": "object",
+      "properties": {
+        "cpu": {"type": "string"}
+      }
+    }
+  },
+  "required": ["image"]
+}`)
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := ParseSchema(testSchema)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		values   map[string]interface{}
+		globals  map[string]interface{}
+		expected []SchemaError
+	}{
+		{
+			name: "valid",
+			values: map[string]interface{}{
+				"image":     "web:1",
+				"replicas":  float64(2),
+				"tier":      "frontend",
+				"labels":    map[string]interface{}{"app": "web"},
+				"ports":     []interface{}{float64(80)},
+				"resources": map[string]interface{}{"cpu": "100m"},
+			},
+		},
+		{
+			name: "invalid",
+			values: map[string]interface{}{
+				"replicas":  1.5,
+				"tier":      "middle",
+				"imge":      "web:1",
+				"ports":     []interface{}{"http"},
+				"resources": map[string]interface{}{"memory": "1Gi"},
+			},
+			expected: []SchemaError{
+				{Path: "imge", Message: "unknown param; expected one of image, labels, ports, replicas, resources, tier"},
+				{Path: "ports.[0]", Message: "expected integer, got string"},
+				{Path: "replicas", Message: "expected integer, got number"},
+				{Path: "resources.memory", Message: "unknown param; expected one of cpu"},
+				{Path: "tier", Message: `must be one of "frontend", "backend"`},
+				{Path: "image", Message: "required param is missing"},
+			},
+		},
+		{
+			name: "globals",
+			values: map[string]interface{}{
+				"image":     "web:1",
+				"registry":  "gcr.io/example",
+				"replicas":  "2",
+				"resources": map[string]interface{}{"cpu": "100m", "memory": "1Gi"},
+			},
+			globals: map[string]interface{}{
+				"registry":  "gcr.io/example",
+				"replicas":  "2",
+				"resources": map[string]interface{}{"memory": "1Gi"},
+			},
+			expected: []SchemaError{
+				{Path: "replicas", Message: "expected integer, got string"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := s.Validate(tc.values, tc.globals)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestSchema_ValidateValue(t *testing.T) {
+	s, err := ParseSchema(testSchema)
+	require.NoError(t, err)
+
+	require.Empty(t, s.ValidateValue([]string{"replicas"}, 3))
+	require.Empty(t, s.ValidateValue([]string{"labels", "app"}, "web"))
+
+	got := s.ValidateValue([]string{"replica"}, 3)
+	require.Equal(t, []SchemaError{
+		{Path: "replica", Message: "unknown param; expected one of image, labels, ports, replicas, resources, tier"},
+	}, got)
+
+	got = s.ValidateValue([]string{"replicas"}, "3")
+	require.Equal(t, []SchemaError{
+		{Path: "replicas", Message: "expected integer, got string"},
+	}, got)
+}
+
+func TestSchema_Lookup(t *testing.T) {
+	s, err := ParseSchema(testSchema)
+	require.NoError(t, err)
+
+	got, err := s.Lookup([]string{"image"})
+	require.NoError(t, err)
+	require.Equal(t, "container image", got.Description)
+
+	got, err = s.Lookup([]string{"labels", "app"})
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	_, err = s.Lookup([]string{"resources", "memory"})
+	require.Error(t, err)
+}
@@ -0,0 +1,258 @@
+package params
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// SecretKeyEnv is the environment variable which holds the base64
+	// encoded secret key.
+	SecretKeyEnv = "KSCOMP_SECRET_KEY"
+	// SecretKeyFileEnv is the environment variable which holds the path of
+	// the secret key file.
+	SecretKeyFileEnv = "KSCOMP_SECRET_KEY_FILE"
+	// SecretKeyFile is the default secret key file in the app root. It should
+	// not be committed.
+	SecretKeyFile = ".secret.key"
+
+	secretKeySize = 32
+	secretPrefix  = "ENC[AES256_GCM,"
+	secretPattern = `ENC\[AES256_GCM,[A-Za-z0-9+/=]+\]`
+)
+
+var (
+	reSecret       = regexp.MustCompile(secretPattern)
+	reSecretValue  = regexp.MustCompile("^" + secretPattern + "$")
+	reQuotedSecret = regexp.MustCompile(`"?` + secretPattern + `"?`)
+)
+
+// IsSecret reports if a value is an encrypted secret.
+func IsSecret(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && reSecretValue.MatchString(s)
+}
+
+// SecretKey encrypts and decrypts secret params. Secrets are stored in params
+// files as envelope strings, e.g. `ENC[AES256_GCM,...]`, which hold the JSON
+// encoded value sealed with AES-256-GCM.
+type SecretKey struct {
+	aead cipher.AEAD
+}
+
+// NewSecretKey creates an instance of SecretKey. The key must be 32 bytes.
+func NewSecretKey(key []byte) (*SecretKey, error) {
+	if len(key) != secretKeySize {
+		return nil, errors.Errorf("secret key is %d bytes; it must be %d", len(key), secretKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretKey{aead: aead}, nil
+}
+
+// IsSecretKeyFile returns true if path is the file LoadSecretKey reads the
+// secret key from. Relative paths are relative to root.
+func IsSecretKeyFile(root, path string) bool {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	path = filepath.Clean(path)
+
+	keyFile := os.Getenv(SecretKeyFileEnv)
+	if keyFile == "" {
+		return path == filepath.Join(root, SecretKeyFile)
+	}
+
+	abs, err := filepath.Abs(keyFile)
+	return err == nil && abs == path
+}
+
+// LoadSecretKey loads the secret key for an app. The key is read from
+// KSCOMP_SECRET_KEY if it is set, otherwise from the file named by
+// KSCOMP_SECRET_KEY_FILE, or .secret.key in the app root. Keys are base64
+// encoded.
+func LoadSecretKey(fs afero.Fs, root string) (*SecretKey, error) {
+	encoded := os.Getenv(SecretKeyEnv)
+	if encoded == "" {
+		path := os.Getenv(SecretKeyFileEnv)
+		if path == "" {
+			path = filepath.Join(root, SecretKeyFile)
+		}
+
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, errors.Errorf("no secret key: set %s or create %s with `head -c %d /dev/urandom | base64`",
+					SecretKeyEnv, path, secretKeySize)
+			}
+			return nil, errors.Wrap(err, "read secret key")
+		}
+
+		encoded = string(b)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.Wrap(err, "decode secret key")
+	}
+
+	return NewSecretKey(key)
+}
+
+// Encrypt encrypts a value as an envelope string.
+func (k *SecretKey) Encrypt(value interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, "encode secret")
+	}
+
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := k.aead.Seal(nonce, nonce, plaintext, nil)
+	return secretPrefix + base64.StdEncoding.EncodeToString(sealed) + "]", nil
+}
+
+// Decrypt decrypts an envelope string.
+func (k *SecretKey) Decrypt(envelope string) (interface{}, error) {
+	if !IsSecret(envelope) {
+		return nil, errors.New("value is not a secret")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(envelope, secretPrefix), "]"))
+	if err != nil {
+		return nil, errors.Wrap(err, "decode secret")
+	}
+
+	size := k.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, errors.New("secret is truncated")
+	}
+
+	plaintext, err := k.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, errors.New("decrypt secret: the secret key doesn't match")
+	}
+
+	return decodeJSON(plaintext)
+}
+
+// HasSecrets reports if formatted text has secrets.
+func HasSecrets(s string) bool {
+	return reSecret.MatchString(s)
+}
+
+// DecryptJSON decrypts the secrets in a JSON document.
+func (k *SecretKey) DecryptJSON(s string) (string, error) {
+	if !HasSecrets(s) {
+		return s, nil
+	}
+
+	doc, err := decodeJSON([]byte(s))
+	if err != nil {
+		return "", err
+	}
+
+	doc, err = k.decryptValue(doc)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (k *SecretKey) decryptValue(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		if IsSecret(t) {
+			return k.Decrypt(t)
+		}
+	case map[string]interface{}:
+		for key := range t {
+			child, err := k.decryptValue(t[key])
+			if err != nil {
+				return nil, errors.Wrap(err, key)
+			}
+
+			t[key] = child
+		}
+	case []interface{}:
+		for i := range t {
+			child, err := k.decryptValue(t[i])
+			if err != nil {
+				return nil, err
+			}
+
+			t[i] = child
+		}
+	}
+
+	return v, nil
+}
+
+// RevealSecrets replaces the secrets in formatted values with their JSON
+// encoded values. If key is nil, secrets are redacted instead.
+func RevealSecrets(s string, key *SecretKey) (string, error) {
+	var err error
+	out := reQuotedSecret.ReplaceAllStringFunc(s, func(m string) string {
+		if key == nil || err != nil {
+			return "<secret>"
+		}
+
+		var v interface{}
+		if v, err = key.Decrypt(strings.Trim(m, `"`)); err != nil {
+			return ""
+		}
+
+		var b []byte
+		b, err = json.Marshal(v)
+		return string(b)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
+// decodeJSON decodes JSON without losing the precision of numbers.
+func decodeJSON(b []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.Wrap(err, "decode json")
+	}
+
+	return v, nil
+}
@@ -0,0 +1,209 @@
+package params
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecretKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestSecretKey(t *testing.T) *SecretKey {
+	key, err := NewSecretKey(testSecretKey)
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestSecretKey_roundTrip(t *testing.T) {
+	key := newTestSecretKey(t)
+
+	values := []interface{}{"hunter2", json.Number("5432"), true, map[string]interface{}{"user": "admin"}}
+	for _, v := range values {
+		envelope, err := key.Encrypt(v)
+		require.NoError(t, err)
+		require.True(t, IsSecret(envelope), envelope)
+
+		got, err := key.Decrypt(envelope)
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	}
+}
+
+func TestSecretKey_Decrypt_wrongKey(t *testing.T) {
+	envelope, err := newTestSecretKey(t).Encrypt("hunter2")
+	require.NoError(t, err)
+
+	other, err := NewSecretKey([]byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(envelope)
+	require.Error(t, err)
+}
+
+func TestNewSecretKey_invalidSize(t *testing.T) {
+	_, err := NewSecretKey([]byte("short"))
+	require.Error(t, err)
+}
+
+func TestLoadSecretKey(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testSecretKey)
+
+	cases := []struct {
+		name    string
+		env     map[string]string
+		file    string
+		isErr   bool
+		content string
+	}{
+		{
+			name: "env",
+			env:  map[string]string{SecretKeyEnv: encoded},
+		},
+		{
+			name:    "default file",
+			file:    "/app/.secret.key",
+			content: encoded + "\n",
+		},
+		{
+			name:    "file from env",
+			env:     map[string]string{SecretKeyFileEnv: "/keys/app.key"},
+			file:    "/keys/app.key",
+			content: encoded,
+		},
+		{
+			name:  "missing",
+			isErr: true,
+		},
+		{
+			name:    "invalid",
+			file:    "/app/.secret.key",
+			content: "not a key",
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			fs := afero.NewMemMapFs()
+			if tc.file != "" {
+				require.NoError(t, afero.WriteFile(fs, tc.file, []byte(tc.content), 0600))
+			}
+
+			key, err := LoadSecretKey(fs, "/app")
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			envelope, err := newTestSecretKey(t).Encrypt("hunter2")
+			require.NoError(t, err)
+
+			got, err := key.Decrypt(envelope)
+			require.NoError(t, err)
+			require.Equal(t, "hunter2", got)
+		})
+	}
+}
+
+func TestIsSecretKeyFile(t *testing.T) {
+	cases := []struct {
+		name     string
+		env      map[string]string
+		path     string
+		expected bool
+	}{
+		{name: "default file", path: "/app/.secret.key", expected: true},
+		{name: "relative", path: "config/../.secret.key", expected: true},
+		{name: "other file", path: "/app/config/.secret.key"},
+		{
+			name:     "file from env",
+			env:      map[string]string{SecretKeyFileEnv: "/app/keys/app.key"},
+			path:     "keys/app.key",
+			expected: true,
+		},
+		{
+			name: "default file with file from env",
+			env:  map[string]string{SecretKeyFileEnv: "/keys/app.key"},
+			path: "/app/.secret.key",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			require.Equal(t, tc.expected, IsSecretKeyFile("/app", tc.path))
+		})
+	}
+}
+
+func TestSecretKey_DecryptJSON(t *testing.T) {
+	key := newTestSecretKey(t)
+
+	password, err := key.Encrypt("hunter2")
+	require.NoError(t, err)
+
+	port, err := key.Encrypt(5432)
+	require.NoError(t, err)
+
+	src := `{"components":{"db":{"password":"` + password + `","port":"` + port + `","replicas":1}}}`
+
+	got, err := key.DecryptJSON(src)
+	require.NoError(t, err)
+
+	expected := `{"components":{"db":{"password":"hunter2","port":5432,"replicas":1}}}`
+	require.Equal(t, expected, got)
+}
+
+func TestRevealSecrets(t *testing.T) {
+	key := newTestSecretKey(t)
+
+	envelope, err := key.Encrypt("hunter2")
+	require.NoError(t, err)
+
+	value := `{"password":"` + envelope + `"}`
+
+	redacted, err := RevealSecrets(value, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"password":<secret>}`, redacted)
+
+	revealed, err := RevealSecrets(value, key)
+	require.NoError(t, err)
+	require.Equal(t, `{"password":"hunter2"}`, revealed)
+}
+
+func TestSet_secret(t *testing.T) {
+	src := `{
+  components: {
+    db: {
+      port: 5432,
+    },
+  },
+}
+`
+
+	envelope, err := newTestSecretKey(t).Encrypt(6543)
+	require.NoError(t, err)
+
+	got, err := Set([]string{"port"}, src, "db", envelope, "components")
+	require.NoError(t, err)
+
+	m, err := ToMap("db", got, "components")
+	require.NoError(t, err)
+	require.Equal(t, envelope, m["port"])
+}
@@ -0,0 +1,17 @@
+local registry = "registry.example.com";
+
+{
+  global: {
+    replicas: 2,
+  },
+  components: {
+    web: {
+      local tag = "1.0",
+      image: registry + "/web:" + tag,
+      name: std.join("-", ["web", "app"]),
+      offset: -1,
+      replicas: $.global.replicas,
+      team: "core",
+    },
+  },
+}
@@ -0,0 +1,13 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components +: {
+    // Insert component parameter overrides here. Ex:
+    // guestbook +: {
+    //   name: "guestbook-dev",
+    //   replicas: params.global.replicas,
+    // },
+    "guestbook-ui" +: {
+    },
+  },
+}
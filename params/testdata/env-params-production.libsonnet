@@ -0,0 +1,12 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components+: {
+    "guestbook-ui"+: {
+      replicas: 5,
+      labels+: {
+        tier: "frontend",
+      },
+    },
+  },
+}
@@ -0,0 +1,25 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components +: {
+    // Insert component parameter overrides here. Ex:
+    // guestbook +: {
+    //   name: "guestbook-dev",
+    //   replicas: params.global.replicas,
+    // },
+    "guestbook-ui" +: {
+      replicas: 3,
+      metadata+: {
+        labels+: {
+          env: "prod",
+        },
+      },
+    },
+    redis+: {
+      replicas: 1,
+    },
+  },
+  global+: {
+    registry: "prod.example.com",
+  },
+}
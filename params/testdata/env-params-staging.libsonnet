@@ -0,0 +1,16 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components+: {
+    "guestbook-ui"+: {
+      image: "guestbook:0.3",
+      replicas: 2,
+      labels+: {
+        tier: "frontend",
+      },
+    },
+    "deployment-0"+: {
+      replicas: 2,
+    },
+  },
+}
@@ -0,0 +1,25 @@
+{
+  global: {
+  },
+  components: {
+    web: {
+      containers: [
+        {
+          name: "web",
+          env: [
+            { name: "MODE", value: "prod" },
+          ],
+        },
+        {
+          name: "sidecar",
+          env: [],
+        },
+      ],
+      ports: [
+        { name: "http", port: 80 },
+        { name: "metrics", port: 9090 },
+      ],
+      args: ["--verbose"],
+    },
+  },
+}
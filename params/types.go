@@ -0,0 +1,149 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ValueType is the type of a param value.
+type ValueType string
+
+const (
+	// TypeString is a string value.
+	TypeString ValueType = "string"
+	// TypeInt is an integer value.
+	TypeInt ValueType = "int"
+	// TypeFloat is a floating point value.
+	TypeFloat ValueType = "float"
+	// TypeBool is a boolean value.
+	TypeBool ValueType = "bool"
+	// TypeJSON is an array or object encoded as JSON.
+	TypeJSON ValueType = "json"
+)
+
+// ValueTypes are the types a value can be decoded as.
+var ValueTypes = []ValueType{TypeString, TypeInt, TypeFloat, TypeBool, TypeJSON}
+
+// ParseValueType converts a string to a ValueType.
+func ParseValueType(s string) (ValueType, error) {
+	for _, t := range ValueTypes {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+
+	return "", errors.Errorf("unknown value type %q", s)
+}
+
+// SplitTypedValue splits a value with an explicit type, e.g. `string:true`
+// or `int:100`. It returns false if the value does not start with a type.
+func SplitTypedValue(s string) (ValueType, string, bool) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	t, err := ParseValueType(parts[0])
+	if err != nil {
+		return "", "", false
+	}
+
+	return t, parts[1], true
+}
+
+// DecodeTypedValue decodes a string as a value of type t.
+func DecodeTypedValue(s string, t ValueType) (interface{}, error) {
+	switch t {
+	case TypeString:
+		return s, nil
+	case TypeInt:
+		i, err := strconv.Atoi(s)
+		if isRangeError(err) {
+			return nil, errors.Errorf("%q is out of range for an int", s)
+		}
+		if err != nil {
+			return nil, errors.Errorf("%q is not an int", s)
+		}
+		return i, nil
+	case TypeFloat:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, errors.Errorf("%q is not a float", s)
+		}
+		return f, nil
+	case TypeBool:
+		b, err := strconv.ParseBool(strings.ToLower(s))
+		if err != nil {
+			return nil, errors.Errorf("%q is not a bool", s)
+		}
+		return b, nil
+	case TypeJSON:
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, errors.Errorf("%q is not valid json", s)
+		}
+		return v, nil
+	default:
+		return nil, errors.Errorf("unknown value type %q", t)
+	}
+}
+
+// kindOf describes the kind of a decoded value. Ints and floats are both
+// numbers since Jsonnet does not distinguish them.
+func kindOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int32, int64, float32, float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// suggestType returns the explicit type of a value.
+func suggestType(v interface{}) ValueType {
+	switch t := v.(type) {
+	case string:
+		return TypeString
+	case bool:
+		return TypeBool
+	case int, int32, int64:
+		return TypeInt
+	case float64:
+		if t == math.Trunc(t) {
+			return TypeInt
+		}
+		return TypeFloat
+	case float32:
+		return TypeFloat
+	default:
+		return TypeJSON
+	}
+}
+
+// typeConflictError is returned when a value would change the type of an
+// existing value.
+type typeConflictError struct {
+	path    string
+	current interface{}
+	value   interface{}
+}
+
+func (e *typeConflictError) Error() string {
+	return fmt.Sprintf("not same types at %s: current value is %s, new value is %s; set it with --type %s to keep the current type or --type %s to change it",
+		e.path, kindOf(e.current), kindOf(e.value), suggestType(e.current), suggestType(e.value))
+}
@@ -0,0 +1,272 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	yaml "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// kustomizationFile is the name of the generated kustomization file.
+	kustomizationFile = "kustomization.yaml"
+)
+
+// DirWriterOpt is an option for configuring DirWriter.
+type DirWriterOpt func(*DirWriter)
+
+// DirWriterWithKustomization generates a kustomization.yaml listing the
+// rendered resources.
+func DirWriterWithKustomization(enabled bool) DirWriterOpt {
+	return func(dw *DirWriter) {
+		dw.kustomization = enabled
+	}
+}
+
+// DirWriterWithPartial marks the rendered components as a subset of the
+// app's components, e.g. when they are filtered. Only the directories of the
+// rendered components are pruned.
+func DirWriterWithPartial(partial bool) DirWriterOpt {
+	return func(dw *DirWriter) {
+		dw.partial = partial
+	}
+}
+
+// DirWriter writes rendered components to a directory tree. Objects are
+// written to `<component-namespace>/<component>/<kind>-<name>.yaml`. The
+// directory is owned by the writer: YAML files which were not written by
+// the current render are removed. The kustomization lists the YAML files in
+// the directory.
+type DirWriter struct {
+	fs            afero.Fs
+	dir           string
+	kustomization bool
+	partial       bool
+}
+
+// NewDirWriter creates an instance of DirWriter.
+func NewDirWriter(fs afero.Fs, dir string, opts ...DirWriterOpt) *DirWriter {
+	dw := &DirWriter{
+		fs:  fs,
+		dir: dir,
+	}
+
+	for _, opt := range opts {
+		opt(dw)
+	}
+
+	return dw
+}
+
+// Write writes rendered components to the directory.
+func (dw *DirWriter) Write(rendered []RenderedComponent) error {
+	if err := dw.fs.MkdirAll(dw.dir, 0755); err != nil {
+		return errors.Wrapf(err, "create %s", dw.dir)
+	}
+
+	written := make(map[string]bool)
+	var componentDirs []string
+
+	for _, rc := range rendered {
+		componentDir := filepath.Join(
+			strings.Trim(rc.Namespace.Name(), "/"),
+			rc.Component.Name(false))
+		componentDirs = append(componentDirs, filepath.Join(dw.dir, componentDir))
+
+		for _, obj := range rc.Objects {
+			rel := filepath.Join(componentDir, objectFileName(obj))
+			if written[rel] {
+				return errors.Errorf("component %q renders more than one %s", rc.Component.Name(true), rel)
+			}
+
+			if err := dw.writeObject(rel, obj); err != nil {
+				return err
+			}
+
+			written[rel] = true
+		}
+	}
+
+	roots := []string{dw.dir}
+	if dw.partial {
+		roots = componentDirs
+	}
+
+	if dw.kustomization {
+		written[kustomizationFile] = true
+	}
+
+	if err := dw.removeStale(roots, written); err != nil {
+		return err
+	}
+
+	if !dw.kustomization {
+		return nil
+	}
+
+	resources, err := dw.resources()
+	if err != nil {
+		return err
+	}
+
+	return dw.writeKustomization(resources)
+}
+
+func (dw *DirWriter) writeObject(rel string, obj *unstructured.Unstructured) error {
+	path := filepath.Join(dw.dir, rel)
+	if err := dw.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "create directory for %s", rel)
+	}
+
+	b, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return errors.Wrapf(err, "convert %s to YAML", rel)
+	}
+
+	return afero.WriteFile(dw.fs, path, b, 0644)
+}
+
+type kustomization struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Resources  []string `yaml:"resources"`
+}
+
+func (dw *DirWriter) writeKustomization(resources []string) error {
+	sort.Strings(resources)
+
+	k := kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  resources,
+	}
+
+	b, err := yaml.Marshal(&k)
+	if err != nil {
+		return errors.Wrap(err, "convert kustomization to YAML")
+	}
+
+	return afero.WriteFile(dw.fs, filepath.Join(dw.dir, kustomizationFile), b, 0644)
+}
+
+// resources returns the YAML files in the directory other than the
+// kustomization.
+func (dw *DirWriter) resources() ([]string, error) {
+	var resources []string
+
+	err := dw.walk(dw.dir, func(path, rel string, fi os.FileInfo) error {
+		if !fi.IsDir() && filepath.Ext(path) == ".yaml" && rel != kustomizationFile {
+			resources = append(resources, filepath.ToSlash(rel))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "list resources")
+	}
+
+	return resources, nil
+}
+
+// removeStale removes YAML files in roots which were not part of this render
+// and any directories left empty by their removal.
+func (dw *DirWriter) removeStale(roots []string, written map[string]bool) error {
+	var dirs []string
+
+	for _, root := range roots {
+		exists, err := afero.DirExists(dw.fs, root)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			continue
+		}
+
+		err = dw.walk(root, func(path, rel string, fi os.FileInfo) error {
+			if fi.IsDir() {
+				// component directories can contain component namespaces
+				if dw.partial && path != root {
+					return filepath.SkipDir
+				}
+
+				dirs = append(dirs, path)
+				return nil
+			}
+
+			if filepath.Ext(path) != ".yaml" || written[rel] {
+				return nil
+			}
+
+			return dw.fs.Remove(path)
+		})
+
+		if err != nil {
+			return errors.Wrap(err, "remove stale files")
+		}
+	}
+
+	// remove the deepest directories first
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	for _, dir := range dirs {
+		fis, err := afero.ReadDir(dw.fs, dir)
+		if err != nil {
+			return err
+		}
+
+		if len(fis) == 0 {
+			if err := dw.fs.Remove(dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walk walks the files and directories in root other than the output
+// directory itself and hidden directories such as .git. Paths are passed to
+// fn with their path relative to the output directory.
+func (dw *DirWriter) walk(root string, fn func(path, rel string, fi os.FileInfo) error) error {
+	return afero.Walk(dw.fs, root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dw.dir, path)
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			if rel == "." {
+				return nil
+			}
+
+			if strings.HasPrefix(fi.Name(), ".") {
+				return filepath.SkipDir
+			}
+		}
+
+		return fn(path, rel, fi)
+	})
+}
+
+// objectFileName creates a file name for an object using its kind and name.
+func objectFileName(obj *unstructured.Unstructured) string {
+	name := obj.GetName()
+	if name == "" {
+		name = obj.GetGenerateName()
+	}
+
+	return fmt.Sprintf("%s-%s.yaml", strings.ToLower(obj.GetKind()), fileNameReplacer.Replace(name))
+}
+
+var fileNameReplacer = strings.NewReplacer("/", "_", ":", "_")
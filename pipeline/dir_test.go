@@ -0,0 +1,187 @@
+package pipeline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bryanl/woowoo/component"
+	cmocks "github.com/bryanl/woowoo/component/mocks"
+	appmocks "github.com/ksonnet/ksonnet/metadata/app/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDirWriter_Write(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	app := &appmocks.App{}
+
+	stale := []string{
+		"/out/old/old/deployment-old.yaml",
+		"/out/kustomization.yaml",
+	}
+	for _, path := range stale {
+		require.NoError(t, afero.WriteFile(fs, path, []byte("old"), 0644))
+	}
+	require.NoError(t, afero.WriteFile(fs, "/out/README.md", []byte("keep"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/out/.git/config.yaml", []byte("keep"), 0644))
+
+	guestbook := &cmocks.Component{}
+	guestbook.On("Name", false).Return("guestbook")
+
+	rbac := &cmocks.Component{}
+	rbac.On("Name", false).Return("rbac")
+
+	rendered := []RenderedComponent{
+		{
+			Namespace: component.NewNamespace(app, ""),
+			Component: guestbook,
+			Objects: []*unstructured.Unstructured{
+				testObject("Service", "guestbook"),
+				testObject("Deployment", "guestbook"),
+			},
+		},
+		{
+			Namespace: component.NewNamespace(app, "infra"),
+			Component: rbac,
+			Objects: []*unstructured.Unstructured{
+				testObject("ClusterRole", "system:auth"),
+			},
+		},
+	}
+
+	dw := NewDirWriter(fs, "/out", DirWriterWithKustomization(true))
+	require.NoError(t, dw.Write(rendered))
+
+	expected := []string{
+		"guestbook/deployment-guestbook.yaml",
+		"guestbook/service-guestbook.yaml",
+		"infra/rbac/clusterrole-system_auth.yaml",
+		"kustomization.yaml",
+		"README.md",
+		".git/config.yaml",
+	}
+	for _, path := range expected {
+		exists, err := afero.Exists(fs, filepath.Join("/out", path))
+		require.NoError(t, err)
+		assert.True(t, exists, "expected %s to exist", path)
+	}
+
+	exists, err := afero.DirExists(fs, "/out/old")
+	require.NoError(t, err)
+	assert.False(t, exists, "expected stale directory to be removed")
+
+	b, err := afero.ReadFile(fs, "/out/guestbook/service-guestbook.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1\nkind: Service\nmetadata:\n  name: guestbook\n", string(b))
+
+	b, err = afero.ReadFile(fs, "/out/kustomization.yaml")
+	require.NoError(t, err)
+
+	expectedKustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- guestbook/deployment-guestbook.yaml
+- guestbook/service-guestbook.yaml
+- infra/rbac/clusterrole-system_auth.yaml
+`
+	assert.Equal(t, expectedKustomization, string(b))
+}
+
+func TestDirWriter_Write_partial(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	app := &appmocks.App{}
+
+	guestbook := &cmocks.Component{}
+	guestbook.On("Name", false).Return("guestbook")
+
+	rbac := &cmocks.Component{}
+	rbac.On("Name", false).Return("rbac")
+
+	rbacRendered := RenderedComponent{
+		Namespace: component.NewNamespace(app, "guestbook"),
+		Component: rbac,
+		Objects: []*unstructured.Unstructured{
+			testObject("ClusterRole", "system:auth"),
+		},
+	}
+
+	rendered := []RenderedComponent{
+		{
+			Namespace: component.NewNamespace(app, ""),
+			Component: guestbook,
+			Objects: []*unstructured.Unstructured{
+				testObject("Service", "guestbook"),
+				testObject("Deployment", "guestbook"),
+			},
+		},
+		rbacRendered,
+	}
+
+	dw := NewDirWriter(fs, "/out", DirWriterWithKustomization(true))
+	require.NoError(t, dw.Write(rendered))
+
+	// the guestbook deployment was removed
+	rendered[0].Objects = rendered[0].Objects[:1]
+
+	dw = NewDirWriter(fs, "/out", DirWriterWithKustomization(true), DirWriterWithPartial(true))
+	require.NoError(t, dw.Write(rendered[:1]))
+
+	expected := map[string]bool{
+		"guestbook/service-guestbook.yaml":            true,
+		"guestbook/deployment-guestbook.yaml":         false,
+		"guestbook/rbac/clusterrole-system_auth.yaml": true,
+	}
+	for path, want := range expected {
+		exists, err := afero.Exists(fs, filepath.Join("/out", path))
+		require.NoError(t, err)
+		assert.Equal(t, want, exists, path)
+	}
+
+	b, err := afero.ReadFile(fs, "/out/kustomization.yaml")
+	require.NoError(t, err)
+
+	expectedKustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- guestbook/rbac/clusterrole-system_auth.yaml
+- guestbook/service-guestbook.yaml
+`
+	assert.Equal(t, expectedKustomization, string(b))
+}
+
+func TestDirWriter_Write_duplicate(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	app := &appmocks.App{}
+
+	c := &cmocks.Component{}
+	c.On("Name", false).Return("guestbook")
+	c.On("Name", true).Return("guestbook")
+
+	rendered := []RenderedComponent{
+		{
+			Namespace: component.NewNamespace(app, ""),
+			Component: c,
+			Objects: []*unstructured.Unstructured{
+				testObject("Service", "guestbook"),
+				testObject("Service", "guestbook"),
+			},
+		},
+	}
+
+	dw := NewDirWriter(fs, "/out")
+	require.Error(t, dw.Write(rendered))
+}
+
+func testObject(kind, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+		},
+	}
+}
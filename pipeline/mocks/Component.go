@@ -118,3 +118,26 @@ func (_m *Component) Namespaces(ksApp app.App, envName string) ([]component.Name
 
 	return r0, r1
 }
+
+// Selection provides a mock function with given fields: ksApp, envName
+func (_m *Component) Selection(ksApp app.App, envName string) (*component.Selection, error) {
+	ret := _m.Called(ksApp, envName)
+
+	var r0 *component.Selection
+	if rf, ok := ret.Get(0).(func(app.App, string) *component.Selection); ok {
+		r0 = rf(ksApp, envName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*component.Selection)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(app.App, string) error); ok {
+		r1 = rf(ksApp, envName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -3,16 +3,16 @@ package pipeline
 import (
 	"bytes"
 	"io"
-	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/bryanl/woowoo/component"
 	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pkg/util/native"
 	jsonnet "github.com/google/go-jsonnet"
 	"github.com/ksonnet/ksonnet/metadata/app"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -26,6 +26,9 @@ type Manager interface {
 	// EnvParams returns the contents of the params file for an env.
 	// TODO: this belongs in app.App
 	EnvParams(ksApp app.App, envName string) (string, error)
+
+	// Selection returns the components included and excluded for an env.
+	Selection(ksApp app.App, envName string) (*component.Selection, error)
 }
 
 type defaultManager struct{}
@@ -43,19 +46,17 @@ func (dc *defaultManager) NSResolveParams(ns component.Namespace) (string, error
 }
 
 func (dc *defaultManager) EnvParams(ksApp app.App, envName string) (string, error) {
-	envParamsPath := filepath.Join(ksApp.Root(), "environments", envName, "params.libsonnet")
-	b, err := afero.ReadFile(ksApp.Fs(), envParamsPath)
-	if err != nil {
-		return "", err
-	}
-
-	return string(b), nil
+	return component.ReadEnvParams(ksApp, envName)
 }
 
 func (dc *defaultManager) Components(ns component.Namespace) ([]component.Component, error) {
 	return ns.Components()
 }
 
+func (dc *defaultManager) Selection(ksApp app.App, envName string) (*component.Selection, error) {
+	return component.ReadSelection(ksApp, envName)
+}
+
 // OverrideComponent overrides the component interface for a pipeline.
 func OverrideComponent(c Manager) Opt {
 	return func(p *Pipeline) {
@@ -63,6 +64,13 @@ func OverrideComponent(c Manager) Opt {
 	}
 }
 
+// Sealed keeps secrets encrypted in the parameters a pipeline creates.
+func Sealed() Opt {
+	return func(p *Pipeline) {
+		p.sealed = true
+	}
+}
+
 // Opt is an option for configuring Pipeline.
 type Opt func(p *Pipeline)
 
@@ -71,6 +79,7 @@ type Pipeline struct {
 	app     app.App
 	envName string
 	cm      Manager
+	sealed  bool
 }
 
 // New creates an instance of Pipeline.
@@ -94,12 +103,17 @@ func (p *Pipeline) Namespaces() ([]component.Namespace, error) {
 }
 
 // EnvParameters creates parameters for a namespace given an environment.
+// Secrets are decrypted unless the pipeline is sealed.
 func (p *Pipeline) EnvParameters(nsName string) (string, error) {
 	ns, err := p.cm.Namespace(p.app, nsName)
 	if err != nil {
 		return "", err
 	}
 
+	if p.sealed {
+		ns = ns.Sealed()
+	}
+
 	paramsStr, err := p.cm.NSResolveParams(ns)
 	if err != nil {
 		return "", err
@@ -114,19 +128,47 @@ func (p *Pipeline) EnvParameters(nsName string) (string, error) {
 
 	vm := jsonnet.MakeVM()
 	vm.ExtCode("__ksonnet/params", paramsStr)
-	return vm.EvaluateSnippet("snippet", string(envParams))
+	native.Register(vm, p.app.Fs(), p.app.Root())
+	evaluated, err := vm.EvaluateSnippet("snippet", string(envParams))
+	if err != nil {
+		return "", err
+	}
+
+	vm = jsonnet.MakeVM()
+	vm.ExtCode("params", evaluated)
+	native.Register(vm, p.app.Fs(), p.app.Root())
+	out, err := vm.EvaluateSnippet("snippet", snippetEnvGlobals)
+	if err != nil {
+		return "", err
+	}
+
+	if p.sealed {
+		return out, nil
+	}
+
+	return component.DecryptSecrets(p.app, out)
 }
 
+// snippetEnvGlobals applies an environment's global params to its components.
+var snippetEnvGlobals = `
+local params = std.extVar("params");
+local applyGlobal = function(key, value) std.mergePatch(value, params.global);
+
+if std.objectHas(params, "global") then {
+	components: std.mapWithKey(applyGlobal, params.components)
+} else params
+`
+
 // Components returns the components that belong to this pipeline.
 func (p *Pipeline) Components(filter []string) ([]component.Component, error) {
-	namespaces, err := p.Namespaces()
+	selected, err := p.selectedNamespaces()
 	if err != nil {
 		return nil, err
 	}
 
 	components := make([]component.Component, 0)
-	for _, ns := range namespaces {
-		members, err := p.cm.Components(ns)
+	for _, sn := range selected {
+		members, err := p.members(sn)
 		if err != nil {
 			return nil, err
 		}
@@ -138,35 +180,263 @@ func (p *Pipeline) Components(filter []string) ([]component.Component, error) {
 	return components, nil
 }
 
-// Objects converts components into Kubernetes objects.
-func (p *Pipeline) Objects(filter []string) ([]*unstructured.Unstructured, error) {
+// selectedNamespace is a namespace with components selected by an
+// environment.
+type selectedNamespace struct {
+	ns component.Namespace
+	// targeted is true if the namespace is an environment target. Only the
+	// included components of other namespaces are selected.
+	targeted  bool
+	selection *component.Selection
+}
+
+// selectedNamespaces returns the environment's target namespaces and the
+// namespaces of components the environment includes.
+func (p *Pipeline) selectedNamespaces() ([]selectedNamespace, error) {
+	selection, err := p.cm.Selection(p.app, p.envName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read component selection for %s", p.envName)
+	}
+
 	namespaces, err := p.Namespaces()
 	if err != nil {
 		return nil, err
 	}
 
-	objects := make([]*unstructured.Unstructured, 0)
+	seen := make(map[string]bool)
+	var selected []selectedNamespace
 	for _, ns := range namespaces {
+		seen[strings.Trim(ns.Name(), "/")] = true
+		selected = append(selected, selectedNamespace{ns: ns, targeted: true, selection: selection})
+	}
+
+	for _, nsName := range selection.IncludedNamespaces() {
+		if seen[nsName] {
+			continue
+		}
+
+		ns, err := p.cm.Namespace(p.app, nsName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "find namespace for included components")
+		}
+
+		selected = append(selected, selectedNamespace{
+			ns:        ns.InEnvironment(p.envName),
+			selection: selection,
+		})
+	}
+
+	return selected, nil
+}
+
+// members returns the components in a namespace the environment selects.
+func (p *Pipeline) members(sn selectedNamespace) ([]component.Component, error) {
+	members, err := p.cm.Components(sn.ns)
+	if err != nil {
+		return nil, err
+	}
+
+	if sn.targeted && sn.selection.IsEmpty() {
+		return members, nil
+	}
+
+	var out []component.Component
+	for _, c := range members {
+		name := c.Name(true)
+		if sn.selection.IsExcluded(name) {
+			continue
+		}
+
+		if !sn.targeted && !sn.selection.IsIncluded(name) {
+			continue
+		}
+
+		out = append(out, c)
+	}
+
+	return out, nil
+}
+
+// RenderedComponent is a component and the objects it rendered.
+type RenderedComponent struct {
+	// Namespace is the component namespace the component belongs to.
+	Namespace component.Namespace
+	// Component is the rendered component.
+	Component component.Component
+	// Objects are the objects the component rendered.
+	Objects []*unstructured.Unstructured
+	// Params are the JSON encoded params the component was rendered with.
+	Params string
+}
+
+// ComponentObjects converts components into Kubernetes objects. The objects are
+// grouped by the component which rendered them.
+func (p *Pipeline) ComponentObjects(filter []string) ([]RenderedComponent, error) {
+	selected, err := p.selectedNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := make([]RenderedComponent, 0)
+	for _, sn := range selected {
+		ns := sn.ns
+
 		paramsStr, err := p.EnvParameters(ns.Name())
 		if err != nil {
 			return nil, err
 		}
 
-		components, err := p.Components(filter)
+		members, err := p.members(sn)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, c := range components {
+		// patches apply even if they are not selected by the filter
+		var patchers []component.Patcher
+		var renderers []component.Component
+		for _, c := range members {
+			if patcher, ok := c.(component.Patcher); ok {
+				patchers = append(patchers, patcher)
+				continue
+			}
+
+			renderers = append(renderers, c)
+		}
+
+		renderers = filterComponents(filter, renderers)
+
+		var nsRendered []RenderedComponent
+		for _, c := range renderers {
 			o, err := c.Objects(paramsStr, p.envName)
 			if err != nil {
 				return nil, err
 			}
 
-			objects = append(objects, o...)
+			nsRendered = append(nsRendered, RenderedComponent{
+				Namespace: ns,
+				Component: c,
+				Objects:   o,
+				Params:    paramsStr,
+			})
+		}
+
+		// renamers are rendered even if they are not selected by the filter,
+		// so the references to their objects are renamed
+		hidden, err := p.renderRenamers(members, renderers, ns, paramsStr)
+		if err != nil {
+			return nil, err
+		}
+
+		all := append(append([]RenderedComponent{}, nsRendered...), hidden...)
+
+		if err := p.patch(patchers, all); err != nil {
+			return nil, err
+		}
+
+		if err := p.rename(all, nsRendered); err != nil {
+			return nil, err
+		}
+
+		rendered = append(rendered, nsRendered...)
+	}
+
+	return rendered, nil
+}
+
+// patch applies patches to the objects rendered in a namespace.
+func (p *Pipeline) patch(patchers []component.Patcher, rendered []RenderedComponent) error {
+	if len(patchers) == 0 {
+		return nil
+	}
+
+	var targets []component.PatchTarget
+	for _, rc := range rendered {
+		for _, obj := range rc.Objects {
+			targets = append(targets, component.PatchTarget{Component: rc.Component, Object: obj})
 		}
 	}
 
+	for _, patcher := range patchers {
+		if err := patcher.Patch(p.envName, targets); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderRenamers renders the renamers in members which weren't rendered.
+func (p *Pipeline) renderRenamers(members, rendered []component.Component, ns component.Namespace, paramsStr string) ([]RenderedComponent, error) {
+	var out []RenderedComponent
+	for _, c := range members {
+		if _, ok := c.(component.Renamer); !ok || containsComponent(rendered, c) {
+			continue
+		}
+
+		o, err := c.Objects(paramsStr, p.envName)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, RenderedComponent{
+			Namespace: ns,
+			Component: c,
+			Objects:   o,
+			Params:    paramsStr,
+		})
+	}
+
+	return out, nil
+}
+
+// rename renames the objects rendered by renamers once they are patched, and
+// the references to them in the rendered objects.
+func (p *Pipeline) rename(all, rendered []RenderedComponent) error {
+	for _, rc := range all {
+		renamer, ok := rc.Component.(component.Renamer)
+		if !ok {
+			continue
+		}
+
+		renames, err := renamer.Rename(rc.Objects)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range renames {
+			for _, target := range rendered {
+				for _, obj := range target.Objects {
+					component.RenameReferences(obj, r)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsComponent(components []component.Component, c component.Component) bool {
+	for i := range components {
+		if components[i] == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Objects converts components into Kubernetes objects.
+func (p *Pipeline) Objects(filter []string) ([]*unstructured.Unstructured, error) {
+	rendered, err := p.ComponentObjects(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]*unstructured.Unstructured, 0)
+	for _, rc := range rendered {
+		objects = append(objects, rc.Objects...)
+	}
+
 	return objects, nil
 }
 
@@ -1,15 +1,19 @@
 package pipeline
 
 import (
+	"encoding/base64"
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
 
 	"github.com/bryanl/woowoo/component"
 	cmocks "github.com/bryanl/woowoo/component/mocks"
+	"github.com/bryanl/woowoo/params"
 	"github.com/bryanl/woowoo/pipeline/mocks"
 	appmocks "github.com/ksonnet/ksonnet/metadata/app/mocks"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -18,6 +22,7 @@ func TestPipeline_Namespaces(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
 		namespaces := []component.Namespace{}
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
 
 		got, err := p.Namespaces()
 		require.NoError(t, err)
@@ -31,6 +36,7 @@ func TestPipeline_EnvParameters(t *testing.T) {
 		ns := component.NewNamespace(p.app, "/")
 		namespaces := []component.Namespace{ns}
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
@@ -42,6 +48,92 @@ func TestPipeline_EnvParameters(t *testing.T) {
 	})
 }
 
+func TestPipeline_EnvParameters_globals(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+		ns := component.NewNamespace(p.app, "/")
+		c.On("Namespace", p.app, "/").Return(ns, nil)
+		c.On("NSResolveParams", ns).Return(`{"components": {"web": {"replicas": 1}}}`, nil)
+
+		envParams := `
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components+: {
+    web+: {
+      replicas: 3,
+    },
+  },
+  global+: {
+    registry: "prod.example.com",
+  },
+}`
+		c.On("EnvParams", p.app, "default").Return(envParams, nil)
+
+		got, err := p.EnvParameters("/")
+		require.NoError(t, err)
+
+		expected := `{
+   "components": {
+      "web": {
+         "registry": "prod.example.com",
+         "replicas": 3
+      }
+   }
+}
+`
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestPipeline_EnvParameters_secrets(t *testing.T) {
+	keyBytes := []byte("0123456789abcdef0123456789abcdef")
+	key, err := params.NewSecretKey(keyBytes)
+	require.NoError(t, err)
+
+	envelope, err := key.Encrypt("hunter2")
+	require.NoError(t, err)
+
+	os.Setenv(params.SecretKeyEnv, base64.StdEncoding.EncodeToString(keyBytes))
+	defer os.Unsetenv(params.SecretKeyEnv)
+
+	cases := []struct {
+		name     string
+		opts     []Opt
+		expected string
+	}{
+		{
+			name:     "decrypted",
+			expected: `{"components":{"db":{"password":"hunter2"}}}`,
+		},
+		{
+			name:     "sealed",
+			opts:     []Opt{Sealed()},
+			expected: envelope,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+				for _, opt := range tc.opts {
+					opt(p)
+				}
+
+				ns := component.NewNamespace(p.app, "/")
+				c.On("Namespace", p.app, "/").Return(ns, nil)
+				c.On("NSResolveParams", mock.Anything).
+					Return(`{"components": {"db": {"password": "`+envelope+`"}}}`, nil)
+				c.On("EnvParams", p.app, "default").Return(`std.extVar("__ksonnet/params")`, nil)
+
+				got, err := p.EnvParameters("/")
+				require.NoError(t, err)
+
+				require.Contains(t, got, tc.expected)
+			})
+		})
+	}
+}
+
 func TestPipeline_Components(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
 		cpnt := &cmocks.Component{}
@@ -50,6 +142,7 @@ func TestPipeline_Components(t *testing.T) {
 		ns := component.NewNamespace(p.app, "/")
 		namespaces := []component.Namespace{ns}
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
@@ -78,6 +171,7 @@ func TestPipeline_Components_filtered(t *testing.T) {
 		ns := component.NewNamespace(p.app, "/")
 		namespaces := []component.Namespace{ns}
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
@@ -92,6 +186,35 @@ func TestPipeline_Components_filtered(t *testing.T) {
 	})
 }
 
+func TestPipeline_Components_selection(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+		cpnt1 := mockComponent("cpnt1")
+		cpnt2 := mockComponent("cpnt2")
+		web := mockComponent("ns2/web")
+		db := mockComponent("ns2/db")
+
+		ns := component.NewNamespace(p.app, "/")
+		ns2 := component.NewNamespace(p.app, "ns2")
+		selection := &component.Selection{
+			Include: []string{"ns2/web"},
+			Exclude: []string{"cpnt2"},
+		}
+
+		c.On("Namespaces", p.app, "default").Return([]component.Namespace{ns}, nil)
+		c.On("Selection", p.app, "default").Return(selection, nil)
+		c.On("Namespace", p.app, "ns2").Return(ns2, nil)
+		c.On("Components", ns).Return([]component.Component{cpnt1, cpnt2}, nil)
+		c.On("Components", ns2.InEnvironment("default")).Return([]component.Component{web, db}, nil)
+
+		got, err := p.Components(nil)
+		require.NoError(t, err)
+
+		expected := []component.Component{cpnt1, web}
+
+		require.Equal(t, expected, got)
+	})
+}
+
 func TestPipeline_Objects(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
 		u := []*unstructured.Unstructured{
@@ -105,6 +228,7 @@ func TestPipeline_Objects(t *testing.T) {
 		ns := component.NewNamespace(p.app, "/")
 		namespaces := []component.Namespace{ns}
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
@@ -117,6 +241,200 @@ func TestPipeline_Objects(t *testing.T) {
 	})
 }
 
+func TestPipeline_ComponentObjects(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+		u := []*unstructured.Unstructured{
+			{},
+		}
+
+		cpnt := &cmocks.Component{}
+		cpnt.On("Objects", mock.Anything, "default").Return(u, nil)
+		components := []component.Component{cpnt}
+
+		ns := component.NewNamespace(p.app, "/")
+		namespaces := []component.Namespace{ns}
+		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
+		c.On("Namespace", p.app, "/").Return(ns, nil)
+		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("EnvParams", p.app, "default").Return("{}", nil)
+		c.On("Components", ns).Return(components, nil)
+
+		got, err := p.ComponentObjects(nil)
+		require.NoError(t, err)
+
+		expected := []RenderedComponent{
+			{Namespace: ns, Component: cpnt, Objects: u, Params: "{ }\n"},
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
+
+type fakePatcher struct {
+	*cmocks.Component
+	targets []component.PatchTarget
+}
+
+func (fp *fakePatcher) Patch(envName string, targets []component.PatchTarget) error {
+	fp.targets = targets
+	for _, target := range targets {
+		target.Object.SetLabels(map[string]string{"env": envName})
+	}
+
+	return nil
+}
+
+func TestPipeline_ComponentObjects_patches(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Service"}}
+
+		cpnt := &cmocks.Component{}
+		cpnt.On("Name", true).Return("web")
+		cpnt.On("Objects", mock.Anything, "default").Return([]*unstructured.Unstructured{obj}, nil)
+
+		patcher := &fakePatcher{Component: &cmocks.Component{}}
+		patcher.Component.On("Name", true).Return("web-patch")
+
+		components := []component.Component{cpnt, patcher}
+
+		ns := component.NewNamespace(p.app, "/")
+		namespaces := []component.Namespace{ns}
+		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
+		c.On("Namespace", p.app, "/").Return(ns, nil)
+		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("EnvParams", p.app, "default").Return("{}", nil)
+		c.On("Components", ns).Return(components, nil)
+
+		// the patch is applied even though the filter does not select it
+		got, err := p.ComponentObjects([]string{"web"})
+		require.NoError(t, err)
+
+		require.Len(t, got, 1)
+		require.Equal(t, cpnt, got[0].Component)
+		require.Equal(t, map[string]string{"env": "default"}, got[0].Objects[0].GetLabels())
+		require.Len(t, patcher.targets, 1)
+	})
+}
+
+type fakeRenamer struct {
+	*cmocks.Component
+}
+
+func (fr *fakeRenamer) Rename(objects []*unstructured.Unstructured) ([]component.Rename, error) {
+	return []component.Rename{{Kind: "ConfigMap", From: "web", To: "web-abc"}}, nil
+}
+
+func TestPipeline_ComponentObjects_renames(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind": "Pod",
+				"spec": map[string]interface{}{
+					"volumes": []interface{}{
+						map[string]interface{}{"configMap": map[string]interface{}{"name": "web"}},
+					},
+				},
+			},
+		}
+
+		cpnt := &cmocks.Component{}
+		cpnt.On("Name", true).Return("web")
+		cpnt.On("Objects", mock.Anything, "default").Return([]*unstructured.Unstructured{obj}, nil)
+
+		renamer := &fakeRenamer{Component: &cmocks.Component{}}
+		renamer.Component.On("Name", true).Return("web-config")
+		renamer.Component.On("Objects", mock.Anything, "default").Return([]*unstructured.Unstructured{}, nil)
+
+		components := []component.Component{cpnt, renamer}
+
+		ns := component.NewNamespace(p.app, "/")
+		namespaces := []component.Namespace{ns}
+		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
+		c.On("Namespace", p.app, "/").Return(ns, nil)
+		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("EnvParams", p.app, "default").Return("{}", nil)
+		c.On("Components", ns).Return(components, nil)
+
+		// the references are renamed even though the filter does not select
+		// the renamer
+		got, err := p.ComponentObjects([]string{"web"})
+		require.NoError(t, err)
+
+		require.Len(t, got, 1)
+
+		volumes := got[0].Objects[0].Object["spec"].(map[string]interface{})["volumes"].([]interface{})
+		require.Equal(t, "web-abc", volumes[0].(map[string]interface{})["configMap"].(map[string]interface{})["name"])
+	})
+}
+
+func TestPipeline_ComponentObjects_patched_generator(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
+		fs := p.app.Fs()
+		stage := func(path, content string) {
+			require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+		}
+
+		stage("/app/components/nginx.generator.yaml", "kind: ConfigMap\nhashSuffix: true\nliterals:\n- LOG_LEVEL=debug\n")
+		stage("/app/components/nginx.patch.yaml", `patches:
+- target:
+    kind: ConfigMap
+    name: nginx
+  strategic:
+    data:
+      LOG_LEVEL: info
+`)
+		stage("/app/patched.generator.yaml", "kind: ConfigMap\nname: nginx\nhashSuffix: true\nliterals:\n- LOG_LEVEL=info\n")
+
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind": "Pod",
+				"spec": map[string]interface{}{
+					"volumes": []interface{}{
+						map[string]interface{}{"configMap": map[string]interface{}{"name": "nginx"}},
+					},
+				},
+			},
+		}
+
+		cpnt := &cmocks.Component{}
+		cpnt.On("Name", true).Return("web")
+		cpnt.On("Objects", mock.Anything, "default").Return([]*unstructured.Unstructured{obj}, nil)
+
+		generator := component.NewGenerator(p.app, "/", "/app/components/nginx.generator.yaml")
+		patch := component.NewPatch(p.app, "/", "/app/components/nginx.patch.yaml")
+
+		components := []component.Component{cpnt, generator, patch}
+
+		ns := component.NewNamespace(p.app, "/")
+		namespaces := []component.Namespace{ns}
+		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
+		c.On("Namespace", p.app, "/").Return(ns, nil)
+		c.On("NSResolveParams", ns).Return("", nil)
+		c.On("EnvParams", p.app, "default").Return("{}", nil)
+		c.On("Components", ns).Return(components, nil)
+
+		// the name is hashed from the patched data
+		patched := component.NewGenerator(p.app, "/", "/app/patched.generator.yaml")
+		expected, err := patched.Objects("", "default")
+		require.NoError(t, err)
+		_, err = patched.Rename(expected)
+		require.NoError(t, err)
+
+		got, err := p.ComponentObjects(nil)
+		require.NoError(t, err)
+
+		require.Len(t, got, 2)
+		require.Equal(t, expected, got[1].Objects)
+
+		volumes := got[0].Objects[0].Object["spec"].(map[string]interface{})["volumes"].([]interface{})
+		require.Equal(t, expected[0].GetName(), volumes[0].(map[string]interface{})["configMap"].(map[string]interface{})["name"])
+	})
+}
+
 func TestPipeline_YAML(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, c *mocks.Component) {
 		u := []*unstructured.Unstructured{
@@ -130,6 +448,7 @@ func TestPipeline_YAML(t *testing.T) {
 		ns := component.NewNamespace(p.app, "/")
 		namespaces := []component.Namespace{ns}
 		c.On("Namespaces", p.app, "default").Return(namespaces, nil)
+		c.On("Selection", p.app, "default").Return(&component.Selection{}, nil)
 		c.On("Namespace", p.app, "/").Return(ns, nil)
 		c.On("NSResolveParams", ns).Return("", nil)
 		c.On("EnvParams", p.app, "default").Return("{}", nil)
@@ -157,6 +476,8 @@ func Test_upgradeParams(t *testing.T) {
 
 func withPipeline(t *testing.T, fn func(p *Pipeline, c *mocks.Component)) {
 	app := &appmocks.App{}
+	app.On("Fs").Return(afero.NewMemMapFs())
+	app.On("Root").Return("/app")
 	envName := "default"
 
 	c := &mocks.Component{}
@@ -0,0 +1,255 @@
+// Package native provides the native functions available to Jsonnet through
+// `std.native`, e.g. `std.native("parseYaml")(str)`.
+package native
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bryanl/woowoo/params"
+	"github.com/ghodss/yaml"
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	amyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Register registers the native functions with a VM. Files are read from fs
+// relative to root, and can't be read from outside of root. The secret key
+// file can't be read.
+func Register(vm *jsonnet.VM, fs afero.Fs, root string) {
+	sandbox := afero.NewBasePathFs(fs, root)
+
+	for _, f := range functions(sandbox, root) {
+		vm.NativeFunction(f)
+	}
+}
+
+func functions(fs afero.Fs, root string) []*jsonnet.NativeFunction {
+	return []*jsonnet.NativeFunction{
+		{
+			Name:   "parseJson",
+			Params: ast.Identifiers{"json"},
+			Func: func(args []interface{}) (interface{}, error) {
+				s, err := stringArg("parseJson", args, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				var v interface{}
+				if err := json.Unmarshal([]byte(s), &v); err != nil {
+					return nil, errors.Wrap(err, "parseJson")
+				}
+
+				return v, nil
+			},
+		},
+		{
+			Name:   "parseYaml",
+			Params: ast.Identifiers{"yaml"},
+			Func: func(args []interface{}) (interface{}, error) {
+				s, err := stringArg("parseYaml", args, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				docs, err := parseYAML(s)
+				if err != nil {
+					return nil, errors.Wrap(err, "parseYaml")
+				}
+
+				return docs, nil
+			},
+		},
+		{
+			Name:   "manifestYamlStream",
+			Params: ast.Identifiers{"value"},
+			Func: func(args []interface{}) (interface{}, error) {
+				docs, ok := args[0].([]interface{})
+				if !ok {
+					return nil, errors.New("manifestYamlStream: value must be an array")
+				}
+
+				var buf bytes.Buffer
+				for _, doc := range docs {
+					b, err := yaml.Marshal(doc)
+					if err != nil {
+						return nil, errors.Wrap(err, "manifestYamlStream")
+					}
+
+					buf.WriteString("---\n")
+					buf.Write(b)
+				}
+
+				return buf.String(), nil
+			},
+		},
+		{
+			Name:   "readFile",
+			Params: ast.Identifiers{"path"},
+			Func: func(args []interface{}) (interface{}, error) {
+				b, err := readFileArg(fs, root, "readFile", args)
+				if err != nil {
+					return nil, err
+				}
+
+				return string(b), nil
+			},
+		},
+		{
+			Name:   "base64FileContents",
+			Params: ast.Identifiers{"path"},
+			Func: func(args []interface{}) (interface{}, error) {
+				b, err := readFileArg(fs, root, "base64FileContents", args)
+				if err != nil {
+					return nil, err
+				}
+
+				return base64.StdEncoding.EncodeToString(b), nil
+			},
+		},
+		{
+			Name:   "sha256",
+			Params: ast.Identifiers{"str"},
+			Func: func(args []interface{}) (interface{}, error) {
+				s, err := stringArg("sha256", args, 0)
+				if err != nil {
+					return nil, err
+				}
+
+				sum := sha256.Sum256([]byte(s))
+				return hex.EncodeToString(sum[:]), nil
+			},
+		},
+		{
+			Name:   "regexMatch",
+			Params: ast.Identifiers{"regex", "str"},
+			Func: func(args []interface{}) (interface{}, error) {
+				re, err := regexArg("regexMatch", args)
+				if err != nil {
+					return nil, err
+				}
+
+				s, err := stringArg("regexMatch", args, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				return re.MatchString(s), nil
+			},
+		},
+		{
+			Name:   "regexSubst",
+			Params: ast.Identifiers{"regex", "src", "repl"},
+			Func: func(args []interface{}) (interface{}, error) {
+				re, err := regexArg("regexSubst", args)
+				if err != nil {
+					return nil, err
+				}
+
+				src, err := stringArg("regexSubst", args, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				repl, err := stringArg("regexSubst", args, 2)
+				if err != nil {
+					return nil, err
+				}
+
+				return re.ReplaceAllString(src, repl), nil
+			},
+		},
+	}
+}
+
+// parseYAML parses a stream of YAML documents. Empty documents are skipped.
+func parseYAML(s string) ([]interface{}, error) {
+	reader := amyaml.NewYAMLReader(bufio.NewReader(bytes.NewBufferString(s)))
+
+	docs := make([]interface{}, 0)
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		b, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+
+		if v != nil {
+			docs = append(docs, v)
+		}
+	}
+
+	return docs, nil
+}
+
+func stringArg(name string, args []interface{}, i int) (string, error) {
+	s, ok := args[i].(string)
+	if !ok {
+		return "", errors.Errorf("%s: argument %d must be a string", name, i+1)
+	}
+
+	return s, nil
+}
+
+func regexArg(name string, args []interface{}) (*regexp.Regexp, error) {
+	s, err := stringArg(name, args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	return re, nil
+}
+
+// readFileArg reads the file named by the first argument. Paths are relative
+// to the root.
+func readFileArg(fs afero.Fs, root, name string, args []interface{}) ([]byte, error) {
+	path, err := stringArg(name, args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if clean := filepath.Clean(path); clean == ".." || strings.HasPrefix(clean, "../") {
+		return nil, errors.Errorf("%s: %s is outside of the app", name, path)
+	}
+
+	if params.IsSecretKeyFile(root, filepath.Join(root, path)) {
+		return nil, errors.Errorf("%s: %s is the secret key file", name, path)
+	}
+
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: read %s", name, path)
+	}
+
+	return b, nil
+}
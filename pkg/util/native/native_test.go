@@ -0,0 +1,110 @@
+package native
+
+import (
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/app/config/app.yaml", []byte("name: web\nport: 80\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/secret.txt", []byte("hunter2"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/app/.secret.key", []byte("c2VjcmV0"), 0644))
+
+	cases := []struct {
+		name     string
+		snippet  string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "parseJson",
+			snippet:  `std.native("parseJson")('{"a": [1, "b"]}')`,
+			expected: `{"a":[1,"b"]}`,
+		},
+		{
+			name:     "parseYaml",
+			snippet:  `std.native("parseYaml")("a: 1\n---\n---\nb: [x]\n")`,
+			expected: `[{"a":1},{"b":["x"]}]`,
+		},
+		{
+			name:     "manifestYamlStream",
+			snippet:  `std.native("manifestYamlStream")([{a: 1}, {b: "x"}])`,
+			expected: `"---\na: 1\n---\nb: x\n"`,
+		},
+		{
+			name:     "readFile",
+			snippet:  `std.native("parseYaml")(std.native("readFile")("config/app.yaml"))[0].port`,
+			expected: `80`,
+		},
+		{
+			name:    "readFile outside of the app",
+			snippet: `std.native("readFile")("../secret.txt")`,
+			isErr:   true,
+		},
+		{
+			name:    "readFile secret key",
+			snippet: `std.native("readFile")(".secret.key")`,
+			isErr:   true,
+		},
+		{
+			name:    "base64FileContents secret key",
+			snippet: `std.native("base64FileContents")("config/../.secret.key")`,
+			isErr:   true,
+		},
+		{
+			name:    "readFile missing",
+			snippet: `std.native("readFile")("missing.yaml")`,
+			isErr:   true,
+		},
+		{
+			name:     "base64FileContents",
+			snippet:  `std.native("base64FileContents")("config/app.yaml")`,
+			expected: `"bmFtZTogd2ViCnBvcnQ6IDgwCg=="`,
+		},
+		{
+			name:     "sha256",
+			snippet:  `std.native("sha256")("hello")`,
+			expected: `"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"`,
+		},
+		{
+			name:     "regexMatch",
+			snippet:  `[std.native("regexMatch")("^v[0-9]+$", "v12"), std.native("regexMatch")("^v[0-9]+$", "latest")]`,
+			expected: `[true,false]`,
+		},
+		{
+			name:     "regexSubst",
+			snippet:  `std.native("regexSubst")("-([0-9]+)$", "web-12", ":$1")`,
+			expected: `"web:12"`,
+		},
+		{
+			name:    "invalid regex",
+			snippet: `std.native("regexMatch")("(", "x")`,
+			isErr:   true,
+		},
+		{
+			name:    "argument type",
+			snippet: `std.native("sha256")(1)`,
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			vm := jsonnet.MakeVM()
+			Register(vm, fs, "/app")
+
+			got, err := vm.EvaluateSnippet("snippet", tc.snippet)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, got)
+		})
+	}
+}
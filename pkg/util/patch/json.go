@@ -0,0 +1,325 @@
+// Package patch applies JSON patches and strategic merge patches to
+// decoded JSON documents.
+package patch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Operation is a JSON Patch (RFC 6902) operation.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// JSON applies JSON Patch operations to doc. Operations are applied in order
+// and doc is only modified if all of them succeed.
+func JSON(doc map[string]interface{}, ops []Operation) error {
+	var root interface{} = deepCopy(doc)
+
+	for i, op := range ops {
+		var err error
+		root, err = applyOperation(root, op)
+		if err != nil {
+			return errors.Wrapf(err, "operation %d (%s %s)", i, op.Op, op.Path)
+		}
+	}
+
+	m, ok := root.(map[string]interface{})
+	if !ok {
+		return errors.New("patched document is not an object")
+	}
+
+	for k := range doc {
+		delete(doc, k)
+	}
+	for k, v := range m {
+		doc[k] = v
+	}
+
+	return nil
+}
+
+func applyOperation(root interface{}, op Operation) (interface{}, error) {
+	switch op.Op {
+	case "add":
+		return add(root, op.Path, deepCopy(op.Value))
+	case "remove":
+		root, _, err := remove(root, op.Path)
+		return root, err
+	case "replace":
+		root, _, err := remove(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, deepCopy(op.Value))
+	case "move":
+		root, v, err := remove(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, v)
+	case "copy":
+		v, err := get(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, op.Path, deepCopy(v))
+	case "test":
+		v, err := get(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(normalize(v), normalize(op.Value)) {
+			return nil, errors.Errorf("test failed: value is %v", v)
+		}
+		return root, nil
+	default:
+		return nil, errors.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// pointer splits a JSON pointer into its unescaped tokens.
+func pointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.Errorf("invalid pointer %q", path)
+	}
+
+	tokens := strings.Split(path[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.Replace(tokens[i], "~1", "/", -1)
+		tokens[i] = strings.Replace(tokens[i], "~0", "~", -1)
+	}
+
+	return tokens, nil
+}
+
+func get(root interface{}, path string) (interface{}, error) {
+	tokens, err := pointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := root
+	for _, token := range tokens {
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			v, ok := t[token]
+			if !ok {
+				return nil, errors.Errorf("%s was not found", path)
+			}
+			cur = v
+		case []interface{}:
+			i, err := index(token, len(t))
+			if err != nil {
+				return nil, err
+			}
+			cur = t[i]
+		default:
+			return nil, errors.Errorf("%s was not found", path)
+		}
+	}
+
+	return cur, nil
+}
+
+// add adds value at path and returns the updated root.
+func add(root interface{}, path string, value interface{}) (interface{}, error) {
+	tokens, err := pointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parentPath := "/" + strings.Join(escape(tokens[:len(tokens)-1]), "/")
+	if len(tokens) == 1 {
+		parentPath = ""
+	}
+
+	last := tokens[len(tokens)-1]
+
+	return update(root, parentPath, func(parent interface{}) (interface{}, error) {
+		switch t := parent.(type) {
+		case map[string]interface{}:
+			t[last] = value
+			return t, nil
+		case []interface{}:
+			if last == "-" {
+				return append(t, value), nil
+			}
+
+			i, err := index(last, len(t)+1)
+			if err != nil {
+				return nil, err
+			}
+
+			t = append(t, nil)
+			copy(t[i+1:], t[i:])
+			t[i] = value
+			return t, nil
+		default:
+			return nil, errors.Errorf("%s was not found", path)
+		}
+	})
+}
+
+// remove removes the value at path. It returns the updated root and the
+// removed value.
+func remove(root interface{}, path string) (interface{}, interface{}, error) {
+	tokens, err := pointer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil, errors.New("unable to remove the document root")
+	}
+
+	parentPath := "/" + strings.Join(escape(tokens[:len(tokens)-1]), "/")
+	if len(tokens) == 1 {
+		parentPath = ""
+	}
+
+	last := tokens[len(tokens)-1]
+
+	var removed interface{}
+	root, err = update(root, parentPath, func(parent interface{}) (interface{}, error) {
+		switch t := parent.(type) {
+		case map[string]interface{}:
+			v, ok := t[last]
+			if !ok {
+				return nil, errors.Errorf("%s was not found", path)
+			}
+			removed = v
+			delete(t, last)
+			return t, nil
+		case []interface{}:
+			i, err := index(last, len(t))
+			if err != nil {
+				return nil, err
+			}
+			removed = t[i]
+			return append(t[:i], t[i+1:]...), nil
+		default:
+			return nil, errors.Errorf("%s was not found", path)
+		}
+	})
+
+	return root, removed, err
+}
+
+// update replaces the value at path with the result of fn. Arrays can
+// change length, so containers are updated on the way back up.
+func update(root interface{}, path string, fn func(interface{}) (interface{}, error)) (interface{}, error) {
+	tokens, err := pointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var walk func(cur interface{}, tokens []string) (interface{}, error)
+	walk = func(cur interface{}, tokens []string) (interface{}, error) {
+		if len(tokens) == 0 {
+			return fn(cur)
+		}
+
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			child, ok := t[tokens[0]]
+			if !ok {
+				return nil, errors.Errorf("%s was not found", path)
+			}
+
+			v, err := walk(child, tokens[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			t[tokens[0]] = v
+			return t, nil
+		case []interface{}:
+			i, err := index(tokens[0], len(t))
+			if err != nil {
+				return nil, err
+			}
+
+			v, err := walk(t[i], tokens[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			t[i] = v
+			return t, nil
+		default:
+			return nil, errors.Errorf("%s was not found", path)
+		}
+	}
+
+	return walk(root, tokens)
+}
+
+func index(token string, length int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= length {
+		return 0, errors.Errorf("invalid array index %q", token)
+	}
+
+	return i, nil
+}
+
+func escape(tokens []string) []string {
+	out := make([]string, len(tokens))
+	for i := range tokens {
+		out[i] = strings.Replace(strings.Replace(tokens[i], "~", "~0", -1), "/", "~1", -1)
+	}
+
+	return out
+}
+
+// normalize converts a value to its JSON representation so values decoded
+// from YAML and JSON compare equal.
+func normalize(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+
+	return out
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[k] = deepCopy(v)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i := range t {
+			a[i] = deepCopy(t[i])
+		}
+		return a
+	default:
+		return v
+	}
+}
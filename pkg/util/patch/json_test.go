@@ -0,0 +1,122 @@
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		doc      string
+		ops      string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "add field",
+			doc:      `{"spec": {}}`,
+			ops:      `[{"op": "add", "path": "/spec/replicas", "value": 3}]`,
+			expected: `{"spec": {"replicas": 3}}`,
+		},
+		{
+			name:     "add to end of array",
+			doc:      `{"args": ["a"]}`,
+			ops:      `[{"op": "add", "path": "/args/-", "value": "b"}]`,
+			expected: `{"args": ["a", "b"]}`,
+		},
+		{
+			name:     "insert into array",
+			doc:      `{"args": ["a", "c"]}`,
+			ops:      `[{"op": "add", "path": "/args/1", "value": "b"}]`,
+			expected: `{"args": ["a", "b", "c"]}`,
+		},
+		{
+			name:     "remove",
+			doc:      `{"metadata": {"labels": {"a": "1", "b": "2"}}}`,
+			ops:      `[{"op": "remove", "path": "/metadata/labels/a"}]`,
+			expected: `{"metadata": {"labels": {"b": "2"}}}`,
+		},
+		{
+			name:     "remove array item",
+			doc:      `{"args": ["a", "b", "c"]}`,
+			ops:      `[{"op": "remove", "path": "/args/1"}]`,
+			expected: `{"args": ["a", "c"]}`,
+		},
+		{
+			name:     "replace",
+			doc:      `{"spec": {"containers": [{"name": "app", "image": "nginx:1.13"}]}}`,
+			ops:      `[{"op": "replace", "path": "/spec/containers/0/image", "value": "nginx:1.14"}]`,
+			expected: `{"spec": {"containers": [{"name": "app", "image": "nginx:1.14"}]}}`,
+		},
+		{
+			name:     "escaped pointer",
+			doc:      `{"metadata": {"annotations": {"a/b": "1"}}}`,
+			ops:      `[{"op": "replace", "path": "/metadata/annotations/a~1b", "value": "2"}]`,
+			expected: `{"metadata": {"annotations": {"a/b": "2"}}}`,
+		},
+		{
+			name:     "move",
+			doc:      `{"a": {"b": 1}, "c": {}}`,
+			ops:      `[{"op": "move", "from": "/a/b", "path": "/c/d"}]`,
+			expected: `{"a": {}, "c": {"d": 1}}`,
+		},
+		{
+			name:     "copy",
+			doc:      `{"a": {"b": 1}}`,
+			ops:      `[{"op": "copy", "from": "/a", "path": "/c"}]`,
+			expected: `{"a": {"b": 1}, "c": {"b": 1}}`,
+		},
+		{
+			name:     "test passes",
+			doc:      `{"a": 1}`,
+			ops:      `[{"op": "test", "path": "/a", "value": 1}, {"op": "replace", "path": "/a", "value": 2}]`,
+			expected: `{"a": 2}`,
+		},
+		{
+			name:  "test fails",
+			doc:   `{"a": 1}`,
+			ops:   `[{"op": "test", "path": "/a", "value": 2}]`,
+			isErr: true,
+		},
+		{
+			name:  "missing path",
+			doc:   `{"a": 1}`,
+			ops:   `[{"op": "replace", "path": "/b/c", "value": 2}]`,
+			isErr: true,
+		},
+		{
+			name:  "unknown operation",
+			doc:   `{"a": 1}`,
+			ops:   `[{"op": "merge", "path": "/a"}]`,
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := decode(t, tc.doc)
+
+			var ops []Operation
+			require.NoError(t, json.Unmarshal([]byte(tc.ops), &ops))
+
+			err := JSON(doc, ops)
+			if tc.isErr {
+				require.Error(t, err)
+				require.Equal(t, decode(t, tc.doc), doc, "document should not be modified")
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, decode(t, tc.expected), doc)
+		})
+	}
+}
+
+func decode(t *testing.T, s string) map[string]interface{} {
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &m))
+	return m
+}
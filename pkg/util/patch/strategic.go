@@ -0,0 +1,171 @@
+package patch
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	directiveKey = "$patch"
+)
+
+// mergeKeys are the fields which identify items in lists of objects, keyed by
+// the name of the list. They follow the merge keys Kubernetes uses for the
+// common pod and service fields. Lists which are not listed here are
+// replaced.
+var mergeKeys = map[string][]string{
+	"containers":       {"name"},
+	"initContainers":   {"name"},
+	"volumes":          {"name"},
+	"volumeMounts":     {"mountPath"},
+	"env":              {"name"},
+	"imagePullSecrets": {"name"},
+	"hostAliases":      {"ip"},
+	"ports":            {"containerPort", "port"},
+}
+
+// StrategicMerge applies a strategic merge patch to doc. Maps are merged,
+// null values delete fields, and lists of objects with a known merge key are
+// merged item by item. `$patch: replace` replaces a map and `$patch: delete`
+// deletes a map or list item.
+func StrategicMerge(doc, patch map[string]interface{}) error {
+	merged, err := mergeMap(deepCopy(doc).(map[string]interface{}), patch)
+	if err != nil {
+		return err
+	}
+
+	for k := range doc {
+		delete(doc, k)
+	}
+	for k, v := range merged {
+		doc[k] = v
+	}
+
+	return nil
+}
+
+func mergeMap(dst, patch map[string]interface{}) (map[string]interface{}, error) {
+	if directive, ok := patch[directiveKey]; ok {
+		switch directive {
+		case "replace":
+			out := deepCopy(patch).(map[string]interface{})
+			delete(out, directiveKey)
+			return out, nil
+		case "delete":
+			return nil, nil
+		default:
+			return nil, errors.Errorf("unknown patch directive %v", directive)
+		}
+	}
+
+	for k, pv := range patch {
+		if pv == nil {
+			delete(dst, k)
+			continue
+		}
+
+		switch t := pv.(type) {
+		case map[string]interface{}:
+			cur, ok := dst[k].(map[string]interface{})
+			if !ok {
+				cur = make(map[string]interface{})
+			}
+
+			merged, err := mergeMap(cur, t)
+			if err != nil {
+				return nil, err
+			}
+
+			if merged == nil {
+				delete(dst, k)
+				continue
+			}
+
+			dst[k] = merged
+		case []interface{}:
+			cur, _ := dst[k].([]interface{})
+
+			merged, err := mergeList(k, cur, t)
+			if err != nil {
+				return nil, err
+			}
+
+			dst[k] = merged
+		default:
+			dst[k] = deepCopy(pv)
+		}
+	}
+
+	return dst, nil
+}
+
+func mergeList(name string, dst, patch []interface{}) ([]interface{}, error) {
+	keys, ok := mergeKeys[name]
+	if !ok {
+		return deepCopy(patch).([]interface{}), nil
+	}
+
+	for _, item := range patch {
+		pm, ok := item.(map[string]interface{})
+		if !ok {
+			// lists of scalars are replaced
+			return deepCopy(patch).([]interface{}), nil
+		}
+
+		key, value, ok := itemKey(pm, keys)
+		if !ok {
+			return nil, errors.Errorf("item in %s has no merge key %v", name, keys)
+		}
+
+		i := findItem(dst, key, value)
+		if i < 0 {
+			if pm[directiveKey] == "delete" {
+				continue
+			}
+
+			dst = append(dst, deepCopy(pm))
+			continue
+		}
+
+		cur, _ := dst[i].(map[string]interface{})
+		merged, err := mergeMap(cur, pm)
+		if err != nil {
+			return nil, err
+		}
+
+		if merged == nil {
+			dst = append(dst[:i], dst[i+1:]...)
+			continue
+		}
+
+		dst[i] = merged
+	}
+
+	return dst, nil
+}
+
+func itemKey(m map[string]interface{}, keys []string) (string, interface{}, bool) {
+	for _, key := range keys {
+		if v, ok := m[key]; ok {
+			return key, v, true
+		}
+	}
+
+	return "", nil, false
+}
+
+func findItem(items []interface{}, key string, value interface{}) int {
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if v, ok := m[key]; ok && reflect.DeepEqual(normalize(v), normalize(value)) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,82 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategicMerge(t *testing.T) {
+	cases := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "merge maps",
+			doc:      `{"metadata": {"labels": {"a": "1"}}, "spec": {"replicas": 1}}`,
+			patch:    `{"metadata": {"labels": {"b": "2"}}, "spec": {"replicas": 3}}`,
+			expected: `{"metadata": {"labels": {"a": "1", "b": "2"}}, "spec": {"replicas": 3}}`,
+		},
+		{
+			name:     "null deletes",
+			doc:      `{"metadata": {"labels": {"a": "1", "b": "2"}}}`,
+			patch:    `{"metadata": {"labels": {"a": null}}}`,
+			expected: `{"metadata": {"labels": {"b": "2"}}}`,
+		},
+		{
+			name:     "merge containers by name",
+			doc:      `{"containers": [{"name": "app", "image": "app:1"}, {"name": "proxy", "image": "envoy:1"}]}`,
+			patch:    `{"containers": [{"name": "proxy", "image": "envoy:2"}, {"name": "log", "image": "fluentd"}]}`,
+			expected: `{"containers": [{"name": "app", "image": "app:1"}, {"name": "proxy", "image": "envoy:2"}, {"name": "log", "image": "fluentd"}]}`,
+		},
+		{
+			name:     "merge ports by port",
+			doc:      `{"ports": [{"port": 80, "targetPort": 8080}]}`,
+			patch:    `{"ports": [{"port": 80, "targetPort": 9090}]}`,
+			expected: `{"ports": [{"port": 80, "targetPort": 9090}]}`,
+		},
+		{
+			name:     "delete list item",
+			doc:      `{"env": [{"name": "A", "value": "1"}, {"name": "B", "value": "2"}]}`,
+			patch:    `{"env": [{"name": "A", "$patch": "delete"}]}`,
+			expected: `{"env": [{"name": "B", "value": "2"}]}`,
+		},
+		{
+			name:     "replace map",
+			doc:      `{"selector": {"a": "1", "b": "2"}}`,
+			patch:    `{"selector": {"$patch": "replace", "c": "3"}}`,
+			expected: `{"selector": {"c": "3"}}`,
+		},
+		{
+			name:     "replace lists without merge keys",
+			doc:      `{"args": ["a", "b"], "tolerations": [{"key": "a"}]}`,
+			patch:    `{"args": ["c"], "tolerations": [{"key": "b"}]}`,
+			expected: `{"args": ["c"], "tolerations": [{"key": "b"}]}`,
+		},
+		{
+			name:  "item without merge key",
+			doc:   `{"containers": [{"name": "app"}]}`,
+			patch: `{"containers": [{"image": "app:2"}]}`,
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := decode(t, tc.doc)
+
+			err := StrategicMerge(doc, decode(t, tc.patch))
+			if tc.isErr {
+				require.Error(t, err)
+				require.Equal(t, decode(t, tc.doc), doc, "document should not be modified")
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, decode(t, tc.expected), doc)
+		})
+	}
+}
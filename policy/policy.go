@@ -0,0 +1,211 @@
+// Package policy checks rendered objects against policies written in jsonnet.
+//
+// Policies live in `policies/*.jsonnet` in the app. A policy is a function
+// which takes a rendered object and returns an array of violations:
+//
+//	function(object)
+//	  [
+//	    { severity: "error", message: "container %s has no resource limits" % c.name }
+//	    for c in object.spec.template.spec.containers
+//	    if !std.objectHas(c, "resources")
+//	  ]
+//
+// Severity is `error` or `warning` and defaults to `error`.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/ksutil"
+	"github.com/bryanl/woowoo/pipeline"
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// policiesDir is the directory which contains policies.
+	policiesDir = "policies"
+
+	// SeverityError is the severity of violations which block apply.
+	SeverityError = "error"
+	// SeverityWarning is the severity of violations which are only reported.
+	SeverityWarning = "warning"
+)
+
+// Violation is a policy violation.
+type Violation struct {
+	// Policy is the name of the policy which reported the violation.
+	Policy string
+	// Severity is the severity of the violation.
+	Severity string
+	// Component is the namespaced name of the component which rendered the object.
+	Component string
+	// Object is the object's name as `kind/name`.
+	Object string
+	// Message describes the violation.
+	Message string
+}
+
+// HasErrors reports if any violations have error severity.
+func HasErrors(violations []Violation) bool {
+	for _, v := range violations {
+		if v.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checker checks rendered objects against policies.
+type Checker struct {
+	app     app.App
+	envName string
+}
+
+// NewChecker creates an instance of Checker.
+func NewChecker(a app.App, envName string) *Checker {
+	return &Checker{
+		app:     a,
+		envName: envName,
+	}
+}
+
+// Policies returns the paths of the policies in the app.
+func (c *Checker) Policies() ([]string, error) {
+	dir := filepath.Join(c.app.Root(), policiesDir)
+
+	exists, err := afero.DirExists(c.app.Fs(), dir)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	fis, err := afero.ReadDir(c.app.Fs(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".jsonnet" {
+			continue
+		}
+
+		paths = append(paths, filepath.Join(dir, fi.Name()))
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Check evaluates all policies over rendered components.
+func (c *Checker) Check(rendered []pipeline.RenderedComponent) ([]Violation, error) {
+	policies, err := c.Policies()
+	if err != nil {
+		return nil, errors.Wrap(err, "find policies")
+	}
+
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	var objects []*unstructured.Unstructured
+	var owners []string
+	for _, rc := range rendered {
+		for _, obj := range rc.Objects {
+			objects = append(objects, obj)
+			owners = append(owners, rc.Component.Name(true))
+		}
+	}
+
+	var violations []Violation
+	for _, path := range policies {
+		found, err := c.evaluate(path, objects)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for i := range found {
+			for _, fv := range found[i] {
+				violations = append(violations, Violation{
+					Policy:    name,
+					Severity:  fv.Severity,
+					Component: owners[i],
+					Object:    ksutil.ObjectName(objects[i]),
+					Message:   fv.Message,
+				})
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+type foundViolation struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// evaluate evaluates a policy over objects. It returns the violations for
+// each object in the same order as the objects.
+func (c *Checker) evaluate(path string, objects []*unstructured.Unstructured) ([][]foundViolation, error) {
+	importer, err := component.NewImporter(c.app, c.envName)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, obj.Object)
+	}
+
+	b, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(importer)
+	vm.ExtCode("__kscomp/objects", string(b))
+
+	snippet := fmt.Sprintf(`local policy = import %q;
+std.map(function(object)
+  local found = policy(object);
+  if found == null then []
+  else if std.type(found) == "array" then found
+  else [found], std.extVar("__kscomp/objects"))`, path)
+
+	out, err := vm.EvaluateSnippet(path, snippet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluate policy %s", filepath.Base(path))
+	}
+
+	var found [][]foundViolation
+	if err := json.Unmarshal([]byte(out), &found); err != nil {
+		return nil, errors.Wrapf(err, "policy %s returned invalid violations", filepath.Base(path))
+	}
+
+	for i := range found {
+		for j := range found[i] {
+			switch found[i][j].Severity {
+			case "":
+				found[i][j].Severity = SeverityError
+			case SeverityError, SeverityWarning:
+			default:
+				return nil, errors.Errorf("policy %s returned invalid severity %q",
+					filepath.Base(path), found[i][j].Severity)
+			}
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bryanl/woowoo/component"
+	cmocks "github.com/bryanl/woowoo/component/mocks"
+	"github.com/bryanl/woowoo/pipeline"
+	appmocks "github.com/ksonnet/ksonnet/metadata/app/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestChecker_Check(t *testing.T) {
+	withChecker(t, func(c *Checker, fs afero.Fs) {
+		for _, name := range []string{"limits.jsonnet", "latest.jsonnet", "lib/images.libsonnet", "README.md"} {
+			stageFile(t, fs, filepath.Join("policies", name))
+		}
+
+		web := &cmocks.Component{}
+		web.On("Name", true).Return("apps/web")
+
+		rendered := []pipeline.RenderedComponent{
+			{
+				Namespace: component.NewNamespace(c.app, "apps"),
+				Component: web,
+				Objects: []*unstructured.Unstructured{
+					deployment("web", map[string]interface{}{
+						"name":  "web",
+						"image": "nginx:latest",
+					}, map[string]interface{}{
+						"name":  "sidecar",
+						"image": "envoy:1.0",
+						"resources": map[string]interface{}{
+							"limits": map[string]interface{}{"cpu": "1"},
+						},
+					}),
+					{
+						Object: map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       "Service",
+							"metadata":   map[string]interface{}{"name": "web"},
+						},
+					},
+				},
+			},
+		}
+
+		got, err := c.Check(rendered)
+		require.NoError(t, err)
+
+		expected := []Violation{
+			{
+				Policy:    "latest",
+				Severity:  SeverityWarning,
+				Component: "apps/web",
+				Object:    "deployment/web",
+				Message:   "image nginx:latest uses the latest tag",
+			},
+			{
+				Policy:    "limits",
+				Severity:  SeverityError,
+				Component: "apps/web",
+				Object:    "deployment/web",
+				Message:   "container web does not set resource limits",
+			},
+		}
+
+		require.Equal(t, expected, got)
+		require.True(t, HasErrors(got))
+	})
+}
+
+func TestChecker_Check_no_policies(t *testing.T) {
+	withChecker(t, func(c *Checker, fs afero.Fs) {
+		got, err := c.Check(nil)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+}
+
+func TestChecker_Check_invalid_severity(t *testing.T) {
+	withChecker(t, func(c *Checker, fs afero.Fs) {
+		policy := `function(object) {severity: "fatal", message: "nope"}`
+		require.NoError(t, afero.WriteFile(fs, "/app/policies/bad.jsonnet", []byte(policy), 0644))
+
+		c1 := &cmocks.Component{}
+		c1.On("Name", true).Return("c1")
+
+		rendered := []pipeline.RenderedComponent{
+			{
+				Namespace: component.NewNamespace(c.app, ""),
+				Component: c1,
+				Objects:   []*unstructured.Unstructured{deployment("web")},
+			},
+		}
+
+		_, err := c.Check(rendered)
+		require.Error(t, err)
+	})
+}
+
+func TestHasErrors(t *testing.T) {
+	require.False(t, HasErrors(nil))
+	require.False(t, HasErrors([]Violation{{Severity: SeverityWarning}}))
+	require.True(t, HasErrors([]Violation{{Severity: SeverityWarning}, {Severity: SeverityError}}))
+}
+
+func deployment(name string, containers ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": containers,
+					},
+				},
+			},
+		},
+	}
+}
+
+func withChecker(t *testing.T, fn func(*Checker, afero.Fs)) {
+	fs := afero.NewMemMapFs()
+
+	app := &appmocks.App{}
+	app.On("Fs").Return(fs)
+	app.On("Root").Return("/app")
+	app.On("LibPath", "default").Return("/app/lib/v1.8.7", nil)
+
+	for _, name := range []string{"k.libsonnet", "k8s.libsonnet"} {
+		require.NoError(t, afero.WriteFile(fs, filepath.Join("/app/lib/v1.8.7", name), []byte("{}"), 0644))
+	}
+
+	fn(NewChecker(app, "default"), fs)
+}
+
+func stageFile(t *testing.T, fs afero.Fs, name string) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	dest := filepath.Join("/app", name)
+	require.NoError(t, fs.MkdirAll(filepath.Dir(dest), 0755))
+	require.NoError(t, afero.WriteFile(fs, dest, b, 0644))
+}
@@ -0,0 +1,6 @@
+{
+  all(object)::
+    if object.kind == "Deployment" then
+      [c.image for c in object.spec.template.spec.containers]
+    else [],
+}
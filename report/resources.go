@@ -0,0 +1,340 @@
+// Package report builds reports about rendered environments.
+package report
+
+import (
+	"sort"
+
+	"github.com/bryanl/woowoo/pipeline"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Resources are the resource requests and limits for a set of objects.
+type Resources struct {
+	Requests Quantities `json:"requests"`
+	Limits   Quantities `json:"limits"`
+}
+
+// Quantities are the amounts of CPU, memory and ephemeral storage.
+type Quantities struct {
+	CPU              resource.Quantity `json:"cpu"`
+	Memory           resource.Quantity `json:"memory"`
+	EphemeralStorage resource.Quantity `json:"ephemeral-storage"`
+}
+
+func (q *Quantities) add(other Quantities) {
+	q.CPU.Add(other.CPU)
+	q.Memory.Add(other.Memory)
+	q.EphemeralStorage.Add(other.EphemeralStorage)
+}
+
+func (q *Quantities) sub(other Quantities) {
+	q.CPU.Sub(other.CPU)
+	q.Memory.Sub(other.Memory)
+	q.EphemeralStorage.Sub(other.EphemeralStorage)
+}
+
+// max sets each quantity to the larger of itself and the quantity in other.
+func (q *Quantities) max(other Quantities) {
+	if other.CPU.Cmp(q.CPU) > 0 {
+		q.CPU = other.CPU
+	}
+	if other.Memory.Cmp(q.Memory) > 0 {
+		q.Memory = other.Memory
+	}
+	if other.EphemeralStorage.Cmp(q.EphemeralStorage) > 0 {
+		q.EphemeralStorage = other.EphemeralStorage
+	}
+}
+
+func (q *Quantities) scale(n int64) {
+	q.CPU = scaleQuantity(q.CPU, n)
+	q.Memory = scaleQuantity(q.Memory, n)
+	q.EphemeralStorage = scaleQuantity(q.EphemeralStorage, n)
+}
+
+func scaleQuantity(q resource.Quantity, n int64) resource.Quantity {
+	return *resource.NewMilliQuantity(q.MilliValue()*n, q.Format)
+}
+
+func (r *Resources) add(other Resources) {
+	r.Requests.add(other.Requests)
+	r.Limits.add(other.Limits)
+}
+
+func (r *Resources) sub(other Resources) {
+	r.Requests.sub(other.Requests)
+	r.Limits.sub(other.Limits)
+}
+
+// Usage is the resources used by a named component or namespace.
+type Usage struct {
+	Name      string    `json:"name"`
+	Resources Resources `json:"resources"`
+}
+
+// ResourceReport is the resources used by an environment.
+type ResourceReport struct {
+	Environment string    `json:"environment"`
+	Components  []Usage   `json:"components"`
+	Namespaces  []Usage   `json:"namespaces"`
+	Total       Resources `json:"total"`
+}
+
+// NewResourceReport creates a resource report for rendered components. It
+// counts the pod templates of Deployments, StatefulSets, DaemonSets, Jobs and
+// CronJobs. DaemonSets are counted once since the number of nodes is unknown.
+func NewResourceReport(envName string, rendered []pipeline.RenderedComponent) (*ResourceReport, error) {
+	components := make(map[string]*Resources)
+	namespaces := make(map[string]*Resources)
+
+	r := &ResourceReport{Environment: envName}
+
+	for _, rc := range rendered {
+		componentName := rc.Component.Name(true)
+		nsName := rc.Namespace.Name()
+
+		if _, ok := components[componentName]; !ok {
+			components[componentName] = &Resources{}
+		}
+		if _, ok := namespaces[nsName]; !ok {
+			namespaces[nsName] = &Resources{}
+		}
+
+		for _, obj := range rc.Objects {
+			res, err := objectResources(obj)
+			if err != nil {
+				return nil, errors.Wrapf(err, "read resources for %s in %s",
+					obj.GetName(), componentName)
+			}
+
+			components[componentName].add(res)
+			namespaces[nsName].add(res)
+			r.Total.add(res)
+		}
+	}
+
+	r.Components = sortedUsage(components)
+	r.Namespaces = sortedUsage(namespaces)
+
+	return r, nil
+}
+
+func sortedUsage(m map[string]*Resources) []Usage {
+	var usage []Usage
+	for name, res := range m {
+		usage = append(usage, Usage{Name: name, Resources: *res})
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Name < usage[j].Name
+	})
+
+	return usage
+}
+
+// Compare returns the change in resources from report `from` to report `to`.
+// Components and namespaces which only exist in one of the reports are
+// included.
+func Compare(from, to *ResourceReport) *ResourceReport {
+	return &ResourceReport{
+		Environment: to.Environment,
+		Components:  compareUsage(from.Components, to.Components),
+		Namespaces:  compareUsage(from.Namespaces, to.Namespaces),
+		Total:       delta(from.Total, to.Total),
+	}
+}
+
+func compareUsage(from, to []Usage) []Usage {
+	m := make(map[string]*Resources)
+	for _, u := range to {
+		res := u.Resources
+		m[u.Name] = &res
+	}
+
+	for _, u := range from {
+		res, ok := m[u.Name]
+		if !ok {
+			res = &Resources{}
+			m[u.Name] = res
+		}
+
+		res.sub(u.Resources)
+	}
+
+	return sortedUsage(m)
+}
+
+func delta(from, to Resources) Resources {
+	to.sub(from)
+	return to
+}
+
+// objectResources returns the resources used by an object's pods.
+func objectResources(obj *unstructured.Unstructured) (Resources, error) {
+	var templatePath []string
+	replicas := int64(1)
+	var err error
+
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		templatePath = []string{"spec", "template", "spec"}
+		replicas, err = intField(obj.Object, 1, "spec", "replicas")
+	case "DaemonSet":
+		templatePath = []string{"spec", "template", "spec"}
+	case "Job":
+		templatePath = []string{"spec", "template", "spec"}
+		replicas, err = intField(obj.Object, 1, "spec", "parallelism")
+	case "CronJob":
+		templatePath = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+		replicas, err = intField(obj.Object, 1, "spec", "jobTemplate", "spec", "parallelism")
+	default:
+		return Resources{}, nil
+	}
+
+	if err != nil {
+		return Resources{}, err
+	}
+
+	podSpec, ok := nestedField(obj.Object, templatePath...).(map[string]interface{})
+	if !ok {
+		return Resources{}, nil
+	}
+
+	res, err := podResources(podSpec)
+	if err != nil {
+		return Resources{}, err
+	}
+
+	res.Requests.scale(replicas)
+	res.Limits.scale(replicas)
+
+	return res, nil
+}
+
+// podResources returns the effective resources for a pod spec. Like the
+// scheduler, it uses the larger of the sum of the containers and the largest
+// init container.
+func podResources(podSpec map[string]interface{}) (Resources, error) {
+	var containers Resources
+	if err := eachContainer(podSpec, "containers", func(res Resources) {
+		containers.add(res)
+	}); err != nil {
+		return Resources{}, err
+	}
+
+	var initContainers Resources
+	if err := eachContainer(podSpec, "initContainers", func(res Resources) {
+		initContainers.Requests.max(res.Requests)
+		initContainers.Limits.max(res.Limits)
+	}); err != nil {
+		return Resources{}, err
+	}
+
+	containers.Requests.max(initContainers.Requests)
+	containers.Limits.max(initContainers.Limits)
+
+	return containers, nil
+}
+
+func eachContainer(podSpec map[string]interface{}, field string, fn func(Resources)) error {
+	containers, ok := podSpec[field].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, item := range containers {
+		container, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		var res Resources
+		var err error
+
+		if res.Requests, err = quantities(container, "requests"); err != nil {
+			return err
+		}
+		if res.Limits, err = quantities(container, "limits"); err != nil {
+			return err
+		}
+
+		fn(res)
+	}
+
+	return nil
+}
+
+func quantities(container map[string]interface{}, field string) (Quantities, error) {
+	var q Quantities
+
+	m, ok := nestedField(container, "resources", field).(map[string]interface{})
+	if !ok {
+		return q, nil
+	}
+
+	for name, dest := range map[string]*resource.Quantity{
+		"cpu":               &q.CPU,
+		"memory":            &q.Memory,
+		"ephemeral-storage": &q.EphemeralStorage,
+	} {
+		v, ok := m[name]
+		if !ok {
+			continue
+		}
+
+		parsed, err := parseQuantity(v)
+		if err != nil {
+			return q, errors.Wrapf(err, "parse %s %s", field, name)
+		}
+
+		*dest = parsed
+	}
+
+	return q, nil
+}
+
+func parseQuantity(v interface{}) (resource.Quantity, error) {
+	switch t := v.(type) {
+	case string:
+		return resource.ParseQuantity(t)
+	case int64:
+		return *resource.NewQuantity(t, resource.DecimalSI), nil
+	case float64:
+		return *resource.NewMilliQuantity(int64(t*1000), resource.DecimalSI), nil
+	default:
+		return resource.Quantity{}, errors.Errorf("invalid quantity %v", v)
+	}
+}
+
+func intField(obj map[string]interface{}, defaultValue int64, fields ...string) (int64, error) {
+	v := nestedField(obj, fields...)
+
+	switch t := v.(type) {
+	case nil:
+		return defaultValue, nil
+	case int64:
+		return t, nil
+	case float64:
+		return int64(t), nil
+	default:
+		return 0, errors.Errorf("invalid value %v for %v", v, fields)
+	}
+}
+
+// nestedField returns the value at a path of fields in obj. It returns nil if
+// the path does not exist.
+func nestedField(obj map[string]interface{}, fields ...string) interface{} {
+	var v interface{} = obj
+	for _, field := range fields {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		v = m[field]
+	}
+
+	return v
+}
@@ -0,0 +1,264 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/bryanl/woowoo/component"
+	cmocks "github.com/bryanl/woowoo/component/mocks"
+	"github.com/bryanl/woowoo/pipeline"
+	appmocks "github.com/ksonnet/ksonnet/metadata/app/mocks"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewResourceReport(t *testing.T) {
+	got, err := NewResourceReport("default", renderedFixture(t, 3))
+	require.NoError(t, err)
+
+	require.Equal(t, "default", got.Environment)
+
+	requireUsage(t, got.Components, "apps/web", "1500m", "768Mi", "3")
+	requireUsage(t, got.Components, "logging", "100m", "", "")
+	requireUsage(t, got.Namespaces, "/", "100m", "", "")
+	requireUsage(t, got.Namespaces, "apps", "1500m", "768Mi", "3")
+
+	require.Equal(t, "1600m", got.Total.Requests.CPU.String())
+	require.Equal(t, "3Gi", got.Total.Limits.Memory.String())
+}
+
+func TestCompare(t *testing.T) {
+	from, err := NewResourceReport("default", renderedFixture(t, 3))
+	require.NoError(t, err)
+
+	to, err := NewResourceReport("prod", renderedFixture(t, 5))
+	require.NoError(t, err)
+
+	got := Compare(from, to)
+
+	require.Equal(t, "prod", got.Environment)
+	requireUsage(t, got.Components, "apps/web", "1", "512Mi", "2")
+	requireUsage(t, got.Components, "logging", "", "", "")
+	require.Equal(t, "1", got.Total.Requests.CPU.String())
+}
+
+func Test_objectResources_kinds(t *testing.T) {
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{
+			container("100m", "1"),
+		},
+	}
+
+	cases := []struct {
+		name     string
+		obj      map[string]interface{}
+		expected string
+	}{
+		{
+			name: "statefulset",
+			obj: map[string]interface{}{
+				"kind": "StatefulSet",
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"template": map[string]interface{}{"spec": podSpec},
+				},
+			},
+			expected: "200m",
+		},
+		{
+			name: "daemonset",
+			obj: map[string]interface{}{
+				"kind": "DaemonSet",
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{"spec": podSpec},
+				},
+			},
+			expected: "100m",
+		},
+		{
+			name: "job",
+			obj: map[string]interface{}{
+				"kind": "Job",
+				"spec": map[string]interface{}{
+					"parallelism": float64(4),
+					"template":    map[string]interface{}{"spec": podSpec},
+				},
+			},
+			expected: "400m",
+		},
+		{
+			name: "cronjob",
+			obj: map[string]interface{}{
+				"kind": "CronJob",
+				"spec": map[string]interface{}{
+					"jobTemplate": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{"spec": podSpec},
+						},
+					},
+				},
+			},
+			expected: "100m",
+		},
+		{
+			name: "init containers larger than containers",
+			obj: map[string]interface{}{
+				"kind": "Deployment",
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers":     []interface{}{container("100m", "1")},
+							"initContainers": []interface{}{container("250m", "1")},
+						},
+					},
+				},
+			},
+			expected: "250m",
+		},
+		{
+			name:     "service",
+			obj:      map[string]interface{}{"kind": "Service"},
+			expected: "0",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := objectResources(&unstructured.Unstructured{Object: tc.obj})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, got.Requests.CPU.String())
+		})
+	}
+}
+
+func Test_objectResources_invalid_quantity(t *testing.T) {
+	obj := deployment(1, container("lots", ""))
+
+	_, err := objectResources(obj)
+	require.Error(t, err)
+}
+
+func Test_scaleQuantity(t *testing.T) {
+	got := scaleQuantity(resource.MustParse("250m"), 100000)
+	require.Equal(t, "25k", got.String())
+
+	got = scaleQuantity(resource.MustParse("256Mi"), 3)
+	require.Equal(t, "768Mi", got.String())
+}
+
+func requireUsage(t *testing.T, usage []Usage, name, cpu, memory, storage string) {
+	for _, u := range usage {
+		if u.Name != name {
+			continue
+		}
+
+		require.Equal(t, orZero(cpu), u.Resources.Requests.CPU.String(), "cpu requests for %s", name)
+		require.Equal(t, orZero(memory), u.Resources.Requests.Memory.String(), "memory requests for %s", name)
+		require.Equal(t, orZero(storage), u.Resources.Limits.EphemeralStorage.String(), "storage limits for %s", name)
+		return
+	}
+
+	t.Fatalf("usage for %s was not found", name)
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+
+	return s
+}
+
+func renderedFixture(t *testing.T, replicas int64) []pipeline.RenderedComponent {
+	a := &appmocks.App{}
+
+	web := &cmocks.Component{}
+	web.On("Name", true).Return("apps/web")
+
+	logging := &cmocks.Component{}
+	logging.On("Name", true).Return("logging")
+
+	webContainer := container("500m", "1")
+	webContainer["resources"].(map[string]interface{})["requests"].(map[string]interface{})["memory"] = "256Mi"
+	webContainer["resources"].(map[string]interface{})["limits"].(map[string]interface{})["memory"] = "1Gi"
+
+	return []pipeline.RenderedComponent{
+		{
+			Namespace: component.NewNamespace(a, "apps"),
+			Component: web,
+			Objects: []*unstructured.Unstructured{
+				deployment(replicas, webContainer),
+				{
+					Object: map[string]interface{}{
+						"kind":     "Service",
+						"metadata": map[string]interface{}{"name": "web"},
+					},
+				},
+			},
+		},
+		{
+			Namespace: component.NewNamespace(a, ""),
+			Component: logging,
+			Objects: []*unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"kind":     "DaemonSet",
+						"metadata": map[string]interface{}{"name": "fluentd"},
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"containers": []interface{}{
+										map[string]interface{}{
+											"name": "fluentd",
+											"resources": map[string]interface{}{
+												"requests": map[string]interface{}{"cpu": "100m"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func deployment(replicas int64, containers ...map[string]interface{}) *unstructured.Unstructured {
+	var items []interface{}
+	for _, c := range containers {
+		items = append(items, c)
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":     "Deployment",
+			"metadata": map[string]interface{}{"name": "web"},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": items,
+					},
+				},
+			},
+		},
+	}
+}
+
+func container(cpu, storage string) map[string]interface{} {
+	limits := map[string]interface{}{}
+	if storage != "" {
+		limits["ephemeral-storage"] = storage
+	}
+
+	return map[string]interface{}{
+		"name": "app",
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": cpu},
+			"limits":   limits,
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package unittest
+
+const (
+	// libraryPath is the import path for the assertion library.
+	libraryPath = "kscomp/test.libsonnet"
+)
+
+// library is the assertion library available to tests. Assertions return true
+// when they pass and raise an error when they fail, so the error trace points
+// at the failing assertion.
+var library = `{
+  // assertEqual fails unless actual is equal to expected.
+  assertEqual(actual, expected)::
+    if actual == expected then true
+    else error 'assertEqual failed\n  actual:   ' + std.toString(actual) + '\n  expected: ' + std.toString(expected),
+
+  // assertTrue fails unless value is true.
+  assertTrue(value, message='')::
+    if value == true then true
+    else error 'assertTrue failed' + (if message == '' then '' else ': ' + message),
+
+  // assertHas fails unless obj contains a dotted path. Array elements are
+  // addressed by their index, e.g. "spec.ports.0.port".
+  assertHas(obj, path)::
+    local parts = std.split(path, '.');
+    local index(arr, part) = [i for i in std.range(0, std.length(arr) - 1) if std.toString(i) == part];
+    local has(v, i) =
+      if i == std.length(parts) then true
+      else if std.type(v) == 'object' then std.objectHas(v, parts[i]) && has(v[parts[i]], i + 1)
+      else if std.type(v) == 'array' then
+        local found = index(v, parts[i]);
+        std.length(found) == 1 && has(v[found[0]], i + 1)
+      else false;
+    if has(obj, 0) then true
+    else error 'assertHas failed: ' + path + ' was not found',
+}
+`
@@ -0,0 +1,8 @@
+{
+  global: {},
+  components: {
+    web: {
+      port: 80,
+    },
+  },
+}
@@ -0,0 +1,243 @@
+// Package unittest runs jsonnet unit tests for components.
+//
+// Tests live in `_test` directories inside component namespaces, e.g.
+// `components/web/_test/guestbook.jsonnet`. A test file evaluates to an
+// object; each visible field is a test which must evaluate to true (or an
+// array of trues). Tests import the component they test and assertions
+// from `kscomp/test.libsonnet`:
+//
+//	local test = import "kscomp/test.libsonnet";
+//	local ui = import "../guestbook-ui.jsonnet";
+//
+//	{
+//	  port: test.assertEqual(ui.items[0].spec.ports[0].port, 80),
+//	  image: test.assertHas(ui, "items.1.spec.template.spec.containers"),
+//	}
+//
+// Components receive their params through `__ksonnet/params`. If the test
+// directory contains a `params.libsonnet`, it is used as the fixture.
+// Otherwise the namespace's resolved params are used.
+package unittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bryanl/woowoo/component"
+	"github.com/bryanl/woowoo/pkg/util/native"
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet/metadata/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	componentsRoot = "components"
+	fixtureFile    = "params.libsonnet"
+)
+
+// Result is the result of a single test.
+type Result struct {
+	// File is the test file relative to the app root.
+	File string
+	// Name is the name of the test.
+	Name string
+	// Err is the reason the test failed. It is nil if the test passed.
+	Err error
+}
+
+// Passed reports if the test passed.
+func (r *Result) Passed() bool {
+	return r.Err == nil
+}
+
+// Runner runs component unit tests.
+type Runner struct {
+	app     app.App
+	envName string
+}
+
+// NewRunner creates an instance of Runner. Tests use the ksonnet libraries
+// for `envName`.
+func NewRunner(a app.App, envName string) *Runner {
+	return &Runner{
+		app:     a,
+		envName: envName,
+	}
+}
+
+// Files returns the test files in the app.
+func (r *Runner) Files() ([]string, error) {
+	root := filepath.Join(r.app.Root(), componentsRoot)
+
+	var files []string
+	err := afero.Walk(r.app.Fs(), root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			return nil
+		}
+
+		if filepath.Base(filepath.Dir(path)) == component.TestDir && filepath.Ext(path) == ".jsonnet" {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "find test files")
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// Run runs the tests in a test file.
+func (r *Runner) Run(file string) ([]Result, error) {
+	relPath, err := filepath.Rel(r.app.Root(), file)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := r.vm(file)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := r.names(vm, file)
+	if err != nil {
+		return []Result{{File: relPath, Err: err}}, nil
+	}
+
+	var results []Result
+	for _, name := range names {
+		result := Result{
+			File: relPath,
+			Name: name,
+			Err:  r.runTest(vm, file, name),
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *Runner) names(vm *jsonnet.VM, file string) ([]string, error) {
+	snippet := fmt.Sprintf(`local tests = import %q;
+if std.type(tests) == "object" then std.objectFields(tests)
+else error "test file must evaluate to an object"`, file)
+
+	out, err := vm.EvaluateSnippet(file, snippet)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err := json.Unmarshal([]byte(out), &names); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func (r *Runner) runTest(vm *jsonnet.VM, file, name string) error {
+	snippet := fmt.Sprintf(`(import %q)[%q]`, file, name)
+
+	out, err := vm.EvaluateSnippet(file, snippet)
+	if err != nil {
+		return err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(out), &v); err != nil {
+		return err
+	}
+
+	if !isPass(v) {
+		return errors.Errorf("test must evaluate to true, got: %s", strings.TrimSpace(out))
+	}
+
+	return nil
+}
+
+// isPass reports if a test value is true or an array of passing values.
+func isPass(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if !isPass(item) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// vm creates a VM for a test file using the importer the pipeline uses.
+func (r *Runner) vm(file string) (*jsonnet.VM, error) {
+	importer, err := component.NewImporter(r.app, r.envName)
+	if err != nil {
+		return nil, err
+	}
+	importer.Add(libraryPath, library)
+
+	paramsStr, err := r.params(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load params for %s", file)
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(importer)
+	vm.ExtCode("__ksonnet/params", paramsStr)
+	native.Register(vm, r.app.Fs(), r.app.Root())
+
+	return vm, nil
+}
+
+// params returns the params fixture for a test file.
+func (r *Runner) params(file string) (string, error) {
+	testDir := filepath.Dir(file)
+
+	fixturePath := filepath.Join(testDir, fixtureFile)
+	exists, err := afero.Exists(r.app.Fs(), fixturePath)
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		b, err := afero.ReadFile(r.app.Fs(), fixturePath)
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+	}
+
+	nsName, err := filepath.Rel(filepath.Join(r.app.Root(), componentsRoot), filepath.Dir(testDir))
+	if err != nil {
+		return "", err
+	}
+
+	if nsName == "." {
+		nsName = ""
+	}
+
+	ns, err := component.GetNamespace(r.app, nsName)
+	if err != nil {
+		return "", err
+	}
+
+	return ns.ResolvedParams()
+}
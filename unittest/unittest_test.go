@@ -0,0 +1,108 @@
+package unittest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	appmocks "github.com/ksonnet/ksonnet/metadata/app/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_Files(t *testing.T) {
+	withRunner(t, func(r *Runner, fs afero.Fs) {
+		stageFile(t, fs, "components/_test/web.jsonnet")
+		stageFile(t, fs, "components/web.jsonnet")
+
+		got, err := r.Files()
+		require.NoError(t, err)
+
+		expected := []string{"/app/components/_test/web.jsonnet"}
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestRunner_Run(t *testing.T) {
+	withRunner(t, func(r *Runner, fs afero.Fs) {
+		stageFile(t, fs, "components/params.libsonnet")
+		stageFile(t, fs, "components/web.jsonnet")
+		stageFile(t, fs, "components/_test/web.jsonnet")
+
+		results, err := r.Run("/app/components/_test/web.jsonnet")
+		require.NoError(t, err)
+
+		got := make(map[string]bool)
+		for _, result := range results {
+			assert.Equal(t, "components/_test/web.jsonnet", result.File)
+			got[result.Name] = result.Passed()
+		}
+
+		expected := map[string]bool{
+			"port":      true,
+			"name":      true,
+			"index":     true,
+			"multiple":  true,
+			"wrongPort": false,
+			"missing":   false,
+			"notTrue":   false,
+		}
+
+		require.Equal(t, expected, got)
+	})
+}
+
+func TestRunner_Run_fixture(t *testing.T) {
+	withRunner(t, func(r *Runner, fs afero.Fs) {
+		stageFile(t, fs, "components/web.jsonnet")
+		stageFile(t, fs, "components/_test/fixture.jsonnet")
+
+		fixture := `{components: {web: {port: 8080}}}`
+		require.NoError(t, afero.WriteFile(fs, "/app/components/_test/params.libsonnet", []byte(fixture), 0644))
+
+		results, err := r.Run("/app/components/_test/fixture.jsonnet")
+		require.NoError(t, err)
+
+		require.Len(t, results, 1)
+		assert.Equal(t, "port", results[0].Name)
+		assert.NoError(t, results[0].Err)
+	})
+}
+
+func TestRunner_Run_invalid_file(t *testing.T) {
+	withRunner(t, func(r *Runner, fs afero.Fs) {
+		require.NoError(t, afero.WriteFile(fs, "/app/components/_test/bad.jsonnet", []byte("[]"), 0644))
+		require.NoError(t, afero.WriteFile(fs, "/app/components/_test/params.libsonnet", []byte("{}"), 0644))
+
+		results, err := r.Run("/app/components/_test/bad.jsonnet")
+		require.NoError(t, err)
+
+		require.Len(t, results, 1)
+		assert.Error(t, results[0].Err)
+	})
+}
+
+func withRunner(t *testing.T, fn func(*Runner, afero.Fs)) {
+	fs := afero.NewMemMapFs()
+
+	app := &appmocks.App{}
+	app.On("Fs").Return(fs)
+	app.On("Root").Return("/app")
+	app.On("LibPath", "default").Return("/app/lib/v1.8.7", nil)
+
+	for _, name := range []string{"k.libsonnet", "k8s.libsonnet"} {
+		require.NoError(t, afero.WriteFile(fs, filepath.Join("/app/lib/v1.8.7", name), []byte("{}"), 0644))
+	}
+
+	fn(NewRunner(app, "default"), fs)
+}
+
+func stageFile(t *testing.T, fs afero.Fs, name string) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	dest := filepath.Join("/app", name)
+	require.NoError(t, fs.MkdirAll(filepath.Dir(dest), 0755))
+	require.NoError(t, afero.WriteFile(fs, dest, b, 0644))
+}